@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// APIKeyHeader is the request header API keys are sent in.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys authenticates requests by the API key in their X-API-Key header.
+type APIKeys struct {
+	// principals maps the SHA-256 digests of the keys to the principals they identify. Only
+	// digests are kept, so keys cannot be recovered from memory and lookups take the same time
+	// whichever part of a key is wrong.
+	principals map[[sha256.Size]byte]*Principal
+}
+
+// LoadAPIKeys reads the API keys in the file at path. See ParseAPIKeys for the file's format.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys, err := ParseAPIKeys(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return keys, nil
+}
+
+// ParseAPIKeys reads a list of API keys. Each line holds a key, the subject it identifies, and
+// optionally a comma-separated list of roles, separated by whitespace. The key may be given as the
+// hex-encoded SHA-256 digest of the key, prefixed with "sha256:", so the file need not hold the key
+// itself. Blank lines and lines starting with "#" are ignored:
+//
+//	# key                  subject     roles
+//	3b2c6a7f0e1d9c8b       mobile-app  user
+//	sha256:9f86d08...      ops         user,admin
+func ParseAPIKeys(r io.Reader) (*APIKeys, error) {
+	keys := &APIKeys{principals: map[[sha256.Size]byte]*Principal{}}
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: expected a key, a subject and optionally roles", line)
+		}
+
+		var digest [sha256.Size]byte
+
+		if hexDigest := strings.TrimPrefix(fields[0], "sha256:"); hexDigest != fields[0] {
+			b, err := hex.DecodeString(hexDigest)
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("line %d: invalid SHA-256 digest", line)
+			}
+
+			copy(digest[:], b)
+		} else {
+			digest = sha256.Sum256([]byte(fields[0]))
+		}
+
+		if _, ok := keys.principals[digest]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key", line)
+		}
+
+		p := &Principal{Subject: fields[1], Method: "api-key"}
+		if len(fields) == 3 {
+			p.Roles = strings.Split(fields[2], ",")
+		}
+
+		keys.principals[digest] = p
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Authenticate returns the principal identified by the request's API key.
+func (k *APIKeys) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	p, ok := k.principals[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,87 @@
+// Package auth authenticates API requests.
+//
+// An Authenticator reads the credentials of a request and returns the Principal they identify. The
+// handler places the Principal on the request context, where resolvers and loaders can read it
+// with FromContext.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request carries no credentials it
+	// understands.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is returned by an Authenticator when the request carries credentials
+	// which are not valid. The returned error wraps it with the reason.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// A Principal is the identity a request was authenticated as.
+type Principal struct {
+	// Subject identifies the user or application, such as the "sub" claim of a JWT.
+	Subject string
+	// Roles are the roles granted to the principal.
+	Roles []string
+	// Method is how the principal was authenticated, such as "api-key" or "jwt".
+	Method string
+}
+
+// HasRole reports whether the principal was granted the role.
+func (p *Principal) HasRole(role string) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// An Authenticator authenticates requests.
+type Authenticator interface {
+	// Authenticate returns the principal identified by the request's credentials.
+	// It returns ErrNoCredentials when the request carries none it understands, and an error
+	// wrapping ErrInvalidCredentials when they are not valid.
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain tries each Authenticator in turn, until one finds credentials it understands.
+type Chain []Authenticator
+
+// Authenticate returns the result of the first Authenticator which finds credentials.
+func (c Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+
+		return p, err
+	}
+
+	return nil, ErrNoCredentials
+}
+
+// The key type is unexported so the principal does not collide with context values set by other
+// packages.
+type key struct{}
+
+// Attach places the principal on the context.
+func Attach(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, key{}, p)
+}
+
+// FromContext returns the principal the request was authenticated as. It returns false for
+// anonymous requests.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(key{}).(*Principal)
+	return p, ok && p != nil
+}
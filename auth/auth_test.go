@@ -0,0 +1,194 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+)
+
+func TestAPIKeys(t *testing.T) {
+	digest := sha256.Sum256([]byte("hashed-key"))
+
+	keys, err := auth.ParseAPIKeys(strings.NewReader(`
+		# key          subject  roles
+		plain-key      web
+		sha256:` + hex.EncodeToString(digest[:]) + ` ops user,admin
+	`))
+	require.NoError(t, err)
+
+	p, err := keys.Authenticate(request(auth.APIKeyHeader, "plain-key"))
+	require.NoError(t, err)
+	require.Equal(t, &auth.Principal{Subject: "web", Method: "api-key"}, p)
+
+	p, err = keys.Authenticate(request(auth.APIKeyHeader, "hashed-key"))
+	require.NoError(t, err)
+	require.Equal(t, "ops", p.Subject)
+	require.True(t, p.HasRole("admin"))
+
+	_, err = keys.Authenticate(request(auth.APIKeyHeader, "wrong-key"))
+	require.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = keys.Authenticate(request("", ""))
+	require.ErrorIs(t, err, auth.ErrNoCredentials)
+
+	_, err = auth.ParseAPIKeys(strings.NewReader("key-without-subject"))
+	require.Error(t, err)
+}
+
+func TestJWT(t *testing.T) {
+	secret := []byte("a-very-secret-hmac-key-of-32-bytes!")
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys, err := auth.ParseJWKS(jwks(t, secret, &private.PublicKey))
+	require.NoError(t, err)
+
+	now := time.Unix(1_600_000_000, 0)
+	j := auth.JWT{Keys: keys, Issuer: "https://issuer.example", Audience: "swapi", Now: func() time.Time { return now }}
+
+	claims := map[string]interface{}{
+		"sub":   "luke",
+		"iss":   "https://issuer.example",
+		"aud":   []string{"swapi", "other"},
+		"exp":   now.Add(time.Hour).Unix(),
+		"roles": []string{"user"},
+	}
+
+	for name, token := range map[string]string{
+		"HS256": signHS256(t, "hmac", secret, claims),
+		"RS256": signRS256(t, "rsa", private, claims),
+	} {
+		p, err := j.Authenticate(request("Authorization", "Bearer "+token))
+		require.NoError(t, err, name)
+		require.Equal(t, &auth.Principal{Subject: "luke", Roles: []string{"user"}, Method: "jwt"}, p, name)
+	}
+
+	invalid := map[string]string{
+		"expired":        signHS256(t, "hmac", secret, with(claims, "exp", now.Add(-time.Hour).Unix())),
+		"no expiry":      signHS256(t, "hmac", secret, with(claims, "exp", nil)),
+		"wrong issuer":   signHS256(t, "hmac", secret, with(claims, "iss", "https://evil.example")),
+		"wrong audience": signHS256(t, "hmac", secret, with(claims, "aud", "other")),
+		"wrong secret":   signHS256(t, "hmac", []byte("another-secret"), claims),
+		"unknown key":    signHS256(t, "missing", secret, claims),
+		// An RSA public key is not secret, so it must never be accepted as an HMAC key.
+		"algorithm confusion": signHS256(t, "rsa", private.PublicKey.N.Bytes(), claims),
+		"alg none":            segment(t, map[string]string{"alg": "none"}) + "." + segment(t, claims) + ".",
+		"malformed":           "not-a-token",
+	}
+
+	for name, token := range invalid {
+		_, err := j.Authenticate(request("Authorization", "Bearer "+token))
+		require.ErrorIs(t, err, auth.ErrInvalidCredentials, name)
+	}
+
+	_, err = j.Authenticate(request("Authorization", "Basic dXNlcjpwYXNz"))
+	require.ErrorIs(t, err, auth.ErrNoCredentials)
+}
+
+func TestChain(t *testing.T) {
+	keys, err := auth.ParseAPIKeys(strings.NewReader("key web"))
+	require.NoError(t, err)
+
+	chain := auth.Chain{auth.JWT{Keys: &auth.KeySet{}}, keys}
+
+	p, err := chain.Authenticate(request(auth.APIKeyHeader, "key"))
+	require.NoError(t, err)
+	require.Equal(t, "web", p.Subject)
+
+	_, err = chain.Authenticate(request("", ""))
+	require.True(t, errors.Is(err, auth.ErrNoCredentials))
+}
+
+func TestContext(t *testing.T) {
+	_, ok := auth.FromContext(context.Background())
+	require.False(t, ok)
+
+	_, ok = auth.FromContext(auth.Attach(context.Background(), nil))
+	require.False(t, ok)
+
+	p, ok := auth.FromContext(auth.Attach(context.Background(), &auth.Principal{Subject: "luke"}))
+	require.True(t, ok)
+	require.Equal(t, "luke", p.Subject)
+}
+
+func request(header, value string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	if header != "" {
+		r.Header.Set(header, value)
+	}
+
+	return r
+}
+
+func jwks(t *testing.T, secret []byte, public *rsa.PublicKey) []byte {
+	b, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "oct", "kid": "hmac", "alg": "HS256", "k": base64.RawURLEncoding.EncodeToString(secret)},
+		{
+			"kty": "RSA", "kid": "rsa", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		},
+	}})
+	require.NoError(t, err)
+
+	return b
+}
+
+func signHS256(t *testing.T, kid string, secret []byte, claims map[string]interface{}) string {
+	signed := segment(t, map[string]string{"alg": "HS256", "kid": kid}) + "." + segment(t, claims)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, kid string, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	signed := segment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func segment(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// with returns a copy of the claims with the claim set, or removed when the value is nil.
+func with(claims map[string]interface{}, claim string, value interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(claims))
+	for k, v := range claims {
+		c[k] = v
+	}
+
+	if value == nil {
+		delete(c, claim)
+	} else {
+		c[claim] = value
+	}
+
+	return c
+}
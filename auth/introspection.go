@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"encoding/json"
+
+	"github.com/tonyghita/graphql-go-example/language"
+	"github.com/tonyghita/graphql-go-example/ordered"
+)
+
+// nameKey is the response key of the names selected by PrepareIntrospection. The leading
+// underscore keeps it apart from the fields of the introspection types.
+const nameKey = "_authName"
+
+// introspectionFields maps the fields of the introspection types to the types they return, so the
+// types of nested selections are known.
+var introspectionFields = map[string]map[string]string{
+	"__Schema":     {"types": "__Type", "queryType": "__Type", "mutationType": "__Type", "subscriptionType": "__Type", "directives": "__Directive"},
+	"__Type":       {"fields": "__Field", "interfaces": "__Type", "possibleTypes": "__Type", "enumValues": "__EnumValue", "inputFields": "__InputValue", "ofType": "__Type"},
+	"__Field":      {"args": "__InputValue", "type": "__Type"},
+	"__InputValue": {"type": "__Type"},
+	"__Directive":  {"args": "__InputValue"},
+}
+
+// PrepareIntrospection rewrites a document which introspects the schema, so that FilterIntrospection
+// can hide the fields a principal may not access from its result. Every selection of a type or a
+// field also selects its name, which FilterIntrospection removes again.
+// It returns false when the document does not introspect the schema, or cannot be parsed.
+func (r *Rules) PrepareIntrospection(query string) (string, bool) {
+	doc, err := language.Parse(query)
+	if err != nil {
+		return query, false
+	}
+
+	p := &preparer{}
+
+	for _, op := range doc.Operations {
+		for _, sel := range op.SelectionSet {
+			if f, ok := sel.(*language.Field); ok {
+				switch f.Name {
+				case "__schema":
+					f.SelectionSet = p.prepare("__Schema", f.SelectionSet)
+				case "__type":
+					f.SelectionSet = p.prepare("__Type", f.SelectionSet)
+				}
+			}
+		}
+	}
+
+	if !p.introspects {
+		return query, false
+	}
+
+	for _, f := range doc.Fragments {
+		if _, ok := introspectionFields[f.TypeCondition]; ok {
+			f.SelectionSet = p.prepare(f.TypeCondition, f.SelectionSet)
+		}
+	}
+
+	return language.Print(doc), true
+}
+
+type preparer struct {
+	introspects bool
+}
+
+func (p *preparer) prepare(typ string, sels []language.Selection) []language.Selection {
+	p.introspects = true
+
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *language.Field:
+			if t, ok := introspectionFields[typ][sel.Name]; ok {
+				sel.SelectionSet = p.prepare(t, sel.SelectionSet)
+			}
+		case *language.InlineFragment:
+			cond := sel.TypeCondition
+			if cond == "" {
+				cond = typ
+			}
+
+			sel.SelectionSet = p.prepare(cond, sel.SelectionSet)
+		}
+	}
+
+	if typ == "__Type" || typ == "__Field" {
+		sels = append(sels, &language.Field{Alias: nameKey, Name: "name"})
+	}
+
+	return sels
+}
+
+// FilterIntrospection removes the fields the principal may not access from the result of a
+// document rewritten by PrepareIntrospection.
+func (r *Rules) FilterIntrospection(p *Principal, data json.RawMessage) (json.RawMessage, error) {
+	v, err := ordered.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r.filter(p, v)
+	ordered.RemoveAll(v, nameKey)
+
+	return json.Marshal(v)
+}
+
+func (r *Rules) filter(p *Principal, v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			r.filter(p, item)
+		}
+	case *ordered.Object:
+		typ, _ := v.Get(nameKey)
+		fields, _ := v.Get("fields")
+
+		if typ, ok := typ.(string); ok {
+			if list, ok := fields.([]interface{}); ok {
+				kept := list[:0]
+
+				for _, f := range list {
+					if o, ok := f.(*ordered.Object); ok {
+						name, _ := o.Get(nameKey)
+						if name, ok := name.(string); ok && !r.Allowed(p, typ+"."+name) {
+							continue
+						}
+					}
+
+					kept = append(kept, f)
+				}
+
+				v.Set("fields", kept)
+			}
+		}
+
+		for _, k := range v.Keys() {
+			child, _ := v.Get(k)
+			r.filter(p, child)
+		}
+	}
+}
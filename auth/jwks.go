@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// A KeySet holds the keys JWTs are verified with, as read from JSON Web Key Sets (RFC 7517).
+// RSA public keys ("kty": "RSA") verify RS256 tokens, and symmetric keys ("kty": "oct") verify
+// HS256 tokens.
+type KeySet struct {
+	keys []*jwk
+}
+
+type jwk struct {
+	id  string
+	alg string
+	// Exactly one of secret and public is set.
+	secret []byte
+	public *rsa.PublicKey
+}
+
+// LoadJWKS reads the key sets in the files at paths. Keys are never fetched over the network.
+func LoadJWKS(paths ...string) (*KeySet, error) {
+	set := &KeySet{}
+
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := ParseJWKS(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		set.keys = append(set.keys, s.keys...)
+	}
+
+	return set, nil
+}
+
+// ParseJWKS reads a JSON Web Key Set. Keys of other types, and keys which are not for signing, are
+// ignored.
+func ParseJWKS(b []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{}
+
+	for i, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key := &jwk{id: k.Kid, alg: k.Alg}
+
+		switch k.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("key %d: invalid RSA public key", i)
+			}
+
+			key.public = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("key %d: invalid symmetric key", i)
+			}
+
+			key.secret = secret
+		default:
+			continue
+		}
+
+		set.keys = append(set.keys, key)
+	}
+
+	if len(set.keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+
+	return set, nil
+}
+
+// find returns the key a token signed with the algorithm should be verified with. A token which
+// names no key may be verified by any key of the right type, as long as there is only one.
+func (s *KeySet) find(kid, alg string) (*jwk, bool) {
+	var found *jwk
+
+	for _, k := range s.keys {
+		if kid != "" && k.id != kid || !k.accepts(alg) {
+			continue
+		}
+
+		if found != nil {
+			return nil, false
+		}
+
+		found = k
+	}
+
+	return found, found != nil
+}
+
+// accepts reports whether the key may verify tokens signed with the algorithm. The algorithm must
+// match the type of the key, so an RSA public key can never be used as an HMAC secret.
+func (k *jwk) accepts(alg string) bool {
+	if k.alg != "" && k.alg != alg {
+		return false
+	}
+
+	switch alg {
+	case "HS256":
+		return k.secret != nil
+	case "RS256":
+		return k.public != nil
+	}
+
+	return false
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// JWT authenticates requests by the JSON Web Token (RFC 7519) in their Authorization header, sent
+// as "Bearer <token>". Tokens must be signed with HS256 or RS256 by a key of the KeySet, must not
+// have expired, and must have an "exp" claim.
+type JWT struct {
+	Keys *KeySet
+	// Issuer, when set, must match the "iss" claim.
+	Issuer string
+	// Audience, when set, must be one of the "aud" claim.
+	Audience string
+	// RolesClaim names the claim holding the principal's roles, either as a list of strings or as
+	// a space-separated string. It defaults to "roles".
+	RolesClaim string
+	// Leeway allows for clock skew when checking the "exp" and "nbf" claims.
+	Leeway time.Duration
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// Authenticate returns the principal identified by the request's bearer token.
+func (j JWT) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+
+	claims, err := j.verify(strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCredentials, err)
+	}
+
+	p := &Principal{Method: "jwt"}
+	p.Subject, _ = claims["sub"].(string)
+
+	if p.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+
+	switch roles := claims[j.rolesClaim()].(type) {
+	case string:
+		p.Roles = strings.Fields(roles)
+	case []interface{}:
+		for _, role := range roles {
+			if s, ok := role.(string); ok {
+				p.Roles = append(p.Roles, s)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// verify checks the token's signature and claims, and returns its claims.
+func (j JWT) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed header")
+	}
+
+	key, ok := j.Keys.find(header.Kid, header.Alg)
+	if !ok {
+		return nil, fmt.Errorf("no key to verify a %s token with key ID %q", header.Alg, header.Kid)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature")
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+
+	switch {
+	case key.secret != nil:
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write(signed)
+
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return nil, fmt.Errorf("invalid signature")
+		}
+	case key.public != nil:
+		digest := sha256.Sum256(signed)
+
+		if err := rsa.VerifyPKCS1v15(key.public, crypto.SHA256, digest[:], sig); err != nil {
+			return nil, fmt.Errorf("invalid signature")
+		}
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed claims")
+	}
+
+	now := j.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+
+	if now.After(time.Unix(int64(exp), 0).Add(j.Leeway)) {
+		return nil, fmt.Errorf("token has expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(j.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, fmt.Errorf("token is not valid yet")
+	}
+
+	if j.Issuer != "" && claims["iss"] != j.Issuer {
+		return nil, fmt.Errorf("unexpected issuer")
+	}
+
+	if j.Audience != "" && !hasAudience(claims["aud"], j.Audience) {
+		return nil, fmt.Errorf("unexpected audience")
+	}
+
+	return claims, nil
+}
+
+func (j JWT) rolesClaim() string {
+	if j.RolesClaim == "" {
+		return "roles"
+	}
+
+	return j.RolesClaim
+}
+
+func (j JWT) now() time.Time {
+	if j.Now == nil {
+		return time.Now()
+	}
+
+	return j.Now()
+}
+
+// hasAudience reports whether the "aud" claim, a string or a list of strings, includes the audience.
+func hasAudience(claim interface{}, audience string) bool {
+	switch aud := claim.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/tonyghita/graphql-go-example/language"
+	"github.com/tonyghita/graphql-go-example/schema"
+)
+
+// Rules are the roles required to access fields, as declared with the @auth directive:
+//
+//	cost: Long @auth(requires: INTERNAL)
+//
+// graphql-go does not execute custom directives, so the resolver of every restricted field calls
+// Authorize, and fields of the query type are checked by CheckRoot before execution.
+type Rules struct {
+	// requires maps field coordinates, such as "Starship.cost", to the role they require.
+	requires map[string]string
+	query    string
+}
+
+// NewRules reads the @auth directives of the schema.
+func NewRules(sdl string) (*Rules, error) {
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := schema.ParseDirectives(sdl)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Rules{requires: map[string]string{}, query: *s.Inspect().QueryType().Name()}
+
+	for coordinate := range dirs {
+		if d, ok := dirs.Find(coordinate, "auth"); ok {
+			role := d.Args["requires"]
+			if role == "" {
+				return nil, fmt.Errorf("%s: @auth requires a role", coordinate)
+			}
+
+			r.requires[coordinate] = role
+		}
+	}
+
+	return r, nil
+}
+
+// Coordinates returns the sorted coordinates of the restricted fields.
+func (r *Rules) Coordinates() []string {
+	coords := make([]string, 0, len(r.requires))
+	for c := range r.requires {
+		coords = append(coords, c)
+	}
+
+	sort.Strings(coords)
+
+	return coords
+}
+
+// Allowed reports whether the principal may access the field at the coordinate. Roles are matched
+// regardless of case, so the role "internal" grants access to fields requiring INTERNAL.
+// A nil principal is anonymous, and may only access unrestricted fields.
+func (r *Rules) Allowed(p *Principal, coordinate string) bool {
+	role, ok := r.requires[coordinate]
+	if !ok {
+		return true
+	}
+
+	if p == nil {
+		return false
+	}
+
+	for _, granted := range p.Roles {
+		if strings.EqualFold(granted, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Rules) check(p *Principal, coordinate string) error {
+	if r.Allowed(p, coordinate) {
+		return nil
+	}
+
+	return &ForbiddenError{Coordinate: coordinate, Role: r.requires[coordinate]}
+}
+
+// A ForbiddenError is returned when the principal may not access a field.
+type ForbiddenError struct {
+	Coordinate string
+	Role       string
+}
+
+func (e *ForbiddenError) Error() string {
+	if e.Role == "" {
+		return fmt.Sprintf("not authorized to access %s", e.Coordinate)
+	}
+
+	return fmt.Sprintf("not authorized to access %s: the %s role is required", e.Coordinate, e.Role)
+}
+
+// Forbidden always returns true: the error is classified as FORBIDDEN for clients.
+func (e *ForbiddenError) Forbidden() bool {
+	return true
+}
+
+// The rulesKey type is unexported so the rules do not collide with context values set by other
+// packages.
+type rulesKey struct{}
+
+// Attach places the rules on the context, for Authorize.
+func (r *Rules) Attach(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rulesKey{}, r)
+}
+
+// Authorize returns a *ForbiddenError when the principal on the context may not access the field
+// at the coordinate. When no rules are attached to the context, access is always forbidden, so a
+// misconfigured server never exposes restricted fields.
+func Authorize(ctx context.Context, coordinate string) error {
+	r, ok := ctx.Value(rulesKey{}).(*Rules)
+	if !ok {
+		return &ForbiddenError{Coordinate: coordinate}
+	}
+
+	p, _ := FromContext(ctx)
+
+	return r.check(p, coordinate)
+}
+
+// CheckRoot returns an error for every field of the query type which the operation selects but
+// the principal may not access, so the operation can fail before anything is executed. Fields
+// skipped with @skip or @include are ignored. Documents which cannot be parsed are left for
+// validation to reject.
+func (r *Rules) CheckRoot(p *Principal, query, opName string, vars map[string]interface{}) []*gqlerrors.QueryError {
+	doc, err := language.Parse(query)
+	if err != nil {
+		return nil
+	}
+
+	op := doc.Operation(opName)
+	if op == nil || op.Type != "query" {
+		return nil
+	}
+
+	var errs []*gqlerrors.QueryError
+
+	var visit func(sels []language.Selection, visited map[string]bool)
+	visit = func(sels []language.Selection, visited map[string]bool) {
+		for _, sel := range sels {
+			switch sel := sel.(type) {
+			case *language.Field:
+				if !included(sel.Directives, vars) {
+					continue
+				}
+
+				if err := r.check(p, r.query+"."+sel.Name); err != nil {
+					errs = append(errs, &gqlerrors.QueryError{
+						Message:       err.Error(),
+						Path:          []interface{}{sel.ResponseKey()},
+						ResolverError: err,
+					})
+				}
+			case *language.InlineFragment:
+				if included(sel.Directives, vars) {
+					visit(sel.SelectionSet, visited)
+				}
+			case *language.FragmentSpread:
+				if f := doc.Fragment(sel.Name); f != nil && !visited[sel.Name] && included(sel.Directives, vars) {
+					visited[sel.Name] = true
+					visit(f.SelectionSet, visited)
+				}
+			}
+		}
+	}
+
+	visit(op.SelectionSet, map[string]bool{})
+
+	return errs
+}
+
+// included evaluates the @skip and @include directives of a selection.
+func included(dirs []*language.Directive, vars map[string]interface{}) bool {
+	condition := func(name string) (bool, bool) {
+		d := language.FindDirective(dirs, name)
+		if d == nil {
+			return false, false
+		}
+
+		arg := d.Argument("if")
+		if arg == nil {
+			return false, false
+		}
+
+		b, _ := arg.Value.Resolve(vars).(bool)
+
+		return b, true
+	}
+
+	if skip, ok := condition("skip"); ok && skip {
+		return false
+	}
+
+	if include, ok := condition("include"); ok && !include {
+		return false
+	}
+
+	return true
+}
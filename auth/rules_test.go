@@ -0,0 +1,146 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+)
+
+const rulesSchema = `
+directive @auth(requires: Role!) on FIELD_DEFINITION
+enum Role { PARTNER INTERNAL }
+
+schema { query: Query }
+
+type Query {
+	ship: Ship
+	audit: String @auth(requires: INTERNAL)
+}
+
+type Ship {
+	name: String
+	cost: Int @auth(requires: INTERNAL)
+}
+`
+
+type rulesRoot struct{}
+
+func (rulesRoot) Ship() *rulesShip { return &rulesShip{} }
+func (rulesRoot) Audit() *string   { return nil }
+
+type rulesShip struct{}
+
+func (rulesShip) Name() *string { return nil }
+func (rulesShip) Cost() *int32  { return nil }
+
+var (
+	partner  = &auth.Principal{Subject: "partner", Roles: []string{"partner"}}
+	internal = &auth.Principal{Subject: "staff", Roles: []string{"internal"}}
+)
+
+func TestRules(t *testing.T) {
+	rules, err := auth.NewRules(rulesSchema)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Query.audit", "Ship.cost"}, rules.Coordinates())
+
+	require.True(t, rules.Allowed(nil, "Ship.name"))
+	require.False(t, rules.Allowed(nil, "Ship.cost"))
+	require.False(t, rules.Allowed(partner, "Ship.cost"))
+	require.True(t, rules.Allowed(internal, "Ship.cost"))
+
+	ctx := auth.Attach(context.Background(), partner)
+
+	var forbidden *auth.ForbiddenError
+	require.ErrorAs(t, auth.Authorize(rules.Attach(ctx), "Ship.cost"), &forbidden)
+	require.Equal(t, "INTERNAL", forbidden.Role)
+	require.NoError(t, auth.Authorize(rules.Attach(ctx), "Ship.name"))
+
+	// Without rules on the context, restricted fields cannot be told apart, so all are forbidden.
+	require.ErrorAs(t, auth.Authorize(ctx, "Ship.name"), &forbidden)
+}
+
+func TestCheckRoot(t *testing.T) {
+	rules, err := auth.NewRules(rulesSchema)
+	require.NoError(t, err)
+
+	query := `query ($skip: Boolean!) { ship { cost } ...F audit @skip(if: $skip) } fragment F on Query { log: audit }`
+
+	errs := rules.CheckRoot(partner, query, "", map[string]interface{}{"skip": false})
+	require.Len(t, errs, 2)
+	require.Equal(t, []interface{}{"log"}, errs[0].Path)
+	require.Equal(t, []interface{}{"audit"}, errs[1].Path)
+
+	errs = rules.CheckRoot(partner, query, "", map[string]interface{}{"skip": true})
+	require.Len(t, errs, 1)
+
+	require.Empty(t, rules.CheckRoot(internal, query, "", map[string]interface{}{"skip": false}))
+}
+
+func TestFilterIntrospection(t *testing.T) {
+	rules, err := auth.NewRules(rulesSchema)
+	require.NoError(t, err)
+
+	s := graphql.MustParseSchema(rulesSchema, &rulesRoot{})
+
+	query := `{
+		__type(name: "Ship") { fields { type { name } } }
+		__schema { types { ...T } }
+	}
+	fragment T on __Type { name fields { name } }`
+
+	_, ok := rules.PrepareIntrospection(`{ ship { name } }`)
+	require.False(t, ok)
+
+	prepared, ok := rules.PrepareIntrospection(query)
+	require.True(t, ok)
+
+	res := s.Exec(context.Background(), prepared, "", nil)
+	require.Empty(t, res.Errors)
+
+	fields := func(p *auth.Principal) (ship, query []string) {
+		data, err := rules.FilterIntrospection(p, res.Data)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "_authName")
+
+		var v struct {
+			Type struct {
+				Fields []json.RawMessage
+			} `json:"__type"`
+			Schema struct {
+				Types []struct {
+					Name   string
+					Fields []struct{ Name string }
+				}
+			} `json:"__schema"`
+		}
+		require.NoError(t, json.Unmarshal(data, &v))
+
+		for _, typ := range v.Schema.Types {
+			for _, f := range typ.Fields {
+				switch typ.Name {
+				case "Ship":
+					ship = append(ship, f.Name)
+				case "Query":
+					query = append(query, f.Name)
+				}
+			}
+		}
+
+		require.Len(t, v.Type.Fields, len(ship))
+
+		return ship, query
+	}
+
+	ship, root := fields(partner)
+	require.Equal(t, []string{"name"}, ship)
+	require.Equal(t, []string{"ship"}, root)
+
+	ship, root = fields(internal)
+	require.Equal(t, []string{"name", "cost"}, ship)
+	require.Equal(t, []string{"ship", "audit"}, root)
+}
@@ -0,0 +1,127 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/cache"
+)
+
+const sdl = `
+directive @cacheControl(maxAge: Int, scope: CacheControlScope) on FIELD_DEFINITION | OBJECT
+enum CacheControlScope { PUBLIC PRIVATE }
+
+schema { query: Query }
+
+type Query {
+	films: [Film!] @cacheControl(maxAge: 60)
+	me: User
+	version: String
+}
+
+type Film @cacheControl(maxAge: 3600) {
+	title: String!
+	director: Person
+	favoritedBy: User
+}
+
+type Person {
+	name: String!
+}
+
+type User @cacheControl(maxAge: 30, scope: PRIVATE) {
+	name: String!
+}
+`
+
+type (
+	root   struct{}
+	film   struct{}
+	person struct{}
+	user   struct{}
+)
+
+func (*root) Films() *[]*film    { return &[]*film{{}} }
+func (*root) Me() *user          { return &user{} }
+func (*root) Version() *string   { v := "1"; return &v }
+func (*film) Title() string      { return "A New Hope" }
+func (*film) Director() *person  { return &person{} }
+func (*film) FavoritedBy() *user { return &user{} }
+func (*person) Name() string     { return "George Lucas" }
+func (*user) Name() string       { return "Luke" }
+
+func TestPolicy(t *testing.T) {
+	hints, err := cache.NewHints(sdl)
+	require.NoError(t, err)
+
+	s, err := graphql.ParseSchema(sdl, &root{}, graphql.Tracer(cache.Tracer{Tracer: trace.OpenTracingTracer{}, Hints: hints}))
+	require.NoError(t, err)
+
+	tests := []struct {
+		query  string
+		policy cache.Policy
+		header string
+	}{
+		{`{ films { title } }`, cache.Policy{MaxAge: 60}, "max-age=60, public"},
+		{`{ films { title favoritedBy { name } } }`, cache.Policy{MaxAge: 30, Scope: cache.Private}, "max-age=30, private"},
+		// A field returning an object type without a hint is not cacheable.
+		{`{ films { director { name } } }`, cache.Policy{}, "no-store"},
+		// Neither is a field of the query type without a hint.
+		{`{ version }`, cache.Policy{}, "no-store"},
+		{`{ __typename }`, cache.Policy{}, "no-store"},
+	}
+
+	for _, tt := range tests {
+		ctx, recorder := cache.Attach(context.Background())
+
+		res := s.Exec(ctx, tt.query, "", nil)
+		require.Empty(t, res.Errors, tt.query)
+
+		require.Equal(t, tt.policy, recorder.Policy(), tt.query)
+		require.Equal(t, tt.header, recorder.Policy().Header(), tt.query)
+	}
+}
+
+func TestNewHintsInvalid(t *testing.T) {
+	_, err := cache.NewHints(sdl + `extend type Person @cacheControl(maxAge: -1)`)
+	require.Error(t, err)
+
+	_, err = cache.NewHints(sdl + `extend type Person @cacheControl(scope: SHARED)`)
+	require.Error(t, err)
+}
+
+func TestResponses(t *testing.T) {
+	var (
+		now    = time.Now()
+		public = cache.Policy{MaxAge: 10}
+		c      = cache.NewResponses(2)
+	)
+
+	c.Put("a", cache.Response{Body: []byte("a"), Policy: public, Stored: now})
+	c.Put("b", cache.Response{Body: []byte("b"), Policy: public, Stored: now})
+
+	// Reading a makes b the least recently used response, so storing c evicts b.
+	_, ok := c.Get("a", now)
+	require.True(t, ok)
+
+	c.Put("c", cache.Response{Body: []byte("c"), Policy: public, Stored: now})
+
+	_, ok = c.Get("b", now)
+	require.False(t, ok)
+	require.Equal(t, 2, c.Len())
+
+	// Expired responses are dropped.
+	_, ok = c.Get("a", now.Add(10*time.Second))
+	require.False(t, ok)
+	require.Equal(t, 1, c.Len())
+
+	// Private and uncacheable responses are never stored.
+	c.Put("private", cache.Response{Policy: cache.Policy{MaxAge: 10, Scope: cache.Private}, Stored: now})
+	c.Put("none", cache.Response{Stored: now})
+	require.Equal(t, 1, c.Len())
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/introspection"
+
+	"github.com/tonyghita/graphql-go-example/schema"
+)
+
+// directive is the name of the directive which declares cache hints.
+const directive = "cacheControl"
+
+// Hints holds the cache policy of every field of a schema which restricts the policy of a
+// response.
+type Hints struct {
+	// fields maps "Type.field" to the policy of the field.
+	// Fields which inherit the policy of their parent are absent.
+	fields map[string]Policy
+}
+
+// NewHints reads the @cacheControl hints of the schema.
+//
+// A field's policy is its own hint or, when it has none, the hint of the object type it returns.
+// Fields of the query type and fields returning an object type which have neither are not
+// cacheable. Other fields, such as those returning scalars, inherit their parent's policy.
+func NewHints(sdl string) (*Hints, error) {
+	dirs, err := schema.ParseDirectives(sdl)
+	if err != nil {
+		return nil, fmt.Errorf("reading directives: %w", err)
+	}
+
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	if err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+
+	types := map[string]Policy{}
+	for coordinate := range dirs {
+		p, ok, err := hint(dirs, coordinate)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			types[coordinate] = p
+		}
+	}
+
+	var (
+		inspected = s.Inspect()
+		query     = *inspected.QueryType().Name()
+		h         = &Hints{fields: map[string]Policy{}}
+	)
+
+	for _, t := range inspected.Types() {
+		if t.Kind() != "OBJECT" || isIntrospection(*t.Name()) {
+			continue
+		}
+
+		for _, f := range *t.Fields(&struct{ IncludeDeprecated bool }{true}) {
+			coordinate := *t.Name() + "." + f.Name()
+
+			if p, ok := types[coordinate]; ok {
+				h.fields[coordinate] = p
+				continue
+			}
+
+			returns := named(f.Type())
+
+			if p, ok := types[*returns.Name()]; ok && composite(returns) {
+				h.fields[coordinate] = p
+				continue
+			}
+
+			if *t.Name() == query || composite(returns) {
+				h.fields[coordinate] = Policy{}
+			}
+		}
+	}
+
+	return h, nil
+}
+
+// Lookup returns the policy of a field, or false when the field inherits the policy of its parent.
+func (h *Hints) Lookup(typeName, fieldName string) (Policy, bool) {
+	if h == nil || isIntrospection(fieldName) {
+		return Policy{}, false
+	}
+
+	p, ok := h.fields[typeName+"."+fieldName]
+	return p, ok
+}
+
+// hint reads the @cacheControl directive applied at the coordinate.
+func hint(dirs schema.Directives, coordinate string) (Policy, bool, error) {
+	d, ok := dirs.Find(coordinate, directive)
+	if !ok {
+		return Policy{}, false, nil
+	}
+
+	var (
+		p   Policy
+		err error
+	)
+
+	if v, ok := d.Args["maxAge"]; ok {
+		if p.MaxAge, err = strconv.Atoi(v); err != nil || p.MaxAge < 0 {
+			return Policy{}, false, fmt.Errorf("%s: invalid maxAge %q", coordinate, v)
+		}
+	}
+
+	if p.Scope, err = parseScope(d.Args["scope"]); err != nil {
+		return Policy{}, false, fmt.Errorf("%s: %w", coordinate, err)
+	}
+
+	return p, true, nil
+}
+
+// named unwraps list and non-null types.
+func named(t *introspection.Type) *introspection.Type {
+	for t.Name() == nil {
+		t = t.OfType()
+	}
+
+	return t
+}
+
+func composite(t *introspection.Type) bool {
+	switch t.Kind() {
+	case "OBJECT", "INTERFACE", "UNION":
+		return true
+	}
+
+	return false
+}
+
+func isIntrospection(name string) bool {
+	return len(name) > 1 && name[:2] == "__"
+}
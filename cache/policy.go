@@ -0,0 +1,77 @@
+// Package cache computes how long GraphQL responses may be cached, and stores responses for reuse.
+//
+// The schema declares cache hints with the @cacheControl directive. While an operation executes,
+// a Tracer records the hint of every field resolved onto a Recorder attached to the context. The
+// resulting Policy is the most restrictive of those hints, and is sent to clients in the
+// Cache-Control header.
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Scope determines who a cached response may be shared with.
+type Scope int
+
+const (
+	// Public responses may be stored by any cache.
+	Public Scope = iota
+	// Private responses may only be stored by the client's own cache.
+	Private
+)
+
+func (s Scope) String() string {
+	if s == Private {
+		return "private"
+	}
+
+	return "public"
+}
+
+// A Policy determines whether and for how long a response may be cached.
+// The zero Policy forbids caching.
+type Policy struct {
+	MaxAge int // The number of seconds the response may be cached for.
+	Scope  Scope
+}
+
+// Cacheable reports whether the policy allows the response to be cached at all.
+func (p Policy) Cacheable() bool {
+	return p.MaxAge > 0
+}
+
+// Restrict returns the most restrictive combination of the policies: the smallest maximum age, and
+// the private scope when either policy is private.
+func (p Policy) Restrict(q Policy) Policy {
+	if q.MaxAge < p.MaxAge {
+		p.MaxAge = q.MaxAge
+	}
+
+	if q.Scope == Private {
+		p.Scope = Private
+	}
+
+	return p
+}
+
+// Header returns the value of the Cache-Control header which expresses the policy.
+func (p Policy) Header() string {
+	if !p.Cacheable() {
+		return "no-store"
+	}
+
+	return fmt.Sprintf("max-age=%d, %s", p.MaxAge, p.Scope)
+}
+
+// parseScope parses the value of a CacheControlScope enum.
+func parseScope(s string) (Scope, error) {
+	switch strings.ToUpper(s) {
+	case "", "PUBLIC":
+		return Public, nil
+	case "PRIVATE":
+		return Private, nil
+	}
+
+	return Public, fmt.Errorf("unknown cache scope %q", s)
+}
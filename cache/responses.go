@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// A Response is a response body stored for reuse.
+type Response struct {
+	Body   []byte
+	ETag   string
+	Policy Policy
+	Stored time.Time
+}
+
+// Age returns the number of whole seconds the response has been stored for.
+func (r Response) Age(now time.Time) int {
+	return int(now.Sub(r.Stored) / time.Second)
+}
+
+// Fresh reports whether the response may still be served.
+func (r Response) Fresh(now time.Time) bool {
+	return now.Before(r.Stored.Add(time.Duration(r.Policy.MaxAge) * time.Second))
+}
+
+// Responses is an in-process store of responses which evicts the least recently used response once
+// it is full. It is safe for concurrent use.
+type Responses struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first.
+}
+
+type entry struct {
+	key  string
+	resp Response
+}
+
+// NewResponses creates a store which holds up to capacity responses.
+func NewResponses(capacity int) *Responses {
+	return &Responses{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Get returns the fresh response stored under the key.
+func (c *Responses) Get(key string, now time.Time) (Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return Response{}, false
+	}
+
+	e := el.Value.(*entry)
+	if !e.resp.Fresh(now) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+
+		return Response{}, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return e.resp, true
+}
+
+// Put stores the response under the key. Responses which are not cacheable or are private are not
+// stored.
+func (c *Responses) Put(key string, resp Response) {
+	if !resp.Policy.Cacheable() || resp.Policy.Scope == Private || c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*entry).resp = resp
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, resp: resp})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// Len returns the number of stored responses.
+func (c *Responses) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
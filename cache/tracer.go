@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/trace"
+)
+
+// The key type is unexported so the recorder does not collide with context values set by other
+// packages.
+type key struct{}
+
+// A Recorder collects the cache policy of a single operation as its fields are resolved.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu         sync.Mutex
+	restricted bool
+	policy     Policy
+}
+
+// Attach places a new Recorder on the context.
+func Attach(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{}
+	return context.WithValue(ctx, key{}, r), r
+}
+
+// Restrict combines the policy with the policy recorded so far.
+func (r *Recorder) Restrict(p Policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.restricted {
+		p = r.policy.Restrict(p)
+	}
+
+	r.policy, r.restricted = p, true
+}
+
+// Policy returns the policy of the operation. It is not cacheable when no field declared a policy.
+func (r *Recorder) Policy() Policy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.policy
+}
+
+// A Tracer records the cache policy of every resolved field on the Recorder attached to the
+// context, then hands over to the wrapped Tracer.
+type Tracer struct {
+	trace.Tracer
+	Hints *Hints
+}
+
+// TraceField records the policy of the field.
+func (t Tracer) TraceField(ctx context.Context, label, typeName, fieldName string, trivial bool, args map[string]interface{}) (context.Context, trace.TraceFieldFinishFunc) {
+	if r, ok := ctx.Value(key{}).(*Recorder); ok {
+		if p, ok := t.Hints.Lookup(typeName, fieldName); ok {
+			r.Restrict(p)
+		}
+	}
+
+	return t.Tracer.TraceField(ctx, label, typeName, fieldName, trivial, args)
+}
+
+// TraceValidation hands over to the wrapped Tracer when it traces validation.
+func (t Tracer) TraceValidation(ctx context.Context) trace.TraceValidationFinishFunc {
+	if v, ok := t.Tracer.(trace.ValidationTracerContext); ok {
+		return v.TraceValidation(ctx)
+	}
+
+	return func([]*errors.QueryError) {}
+}
@@ -0,0 +1,94 @@
+// Command swapigen generates the boilerplate loaders and resolvers for the SWAPI resources.
+//
+// The generator reads the GraphQL schema embedded in the schema package, the struct definitions
+// in the swapi package, and mapping.json, which names each resource and maps schema fields to swapi
+// struct fields wherever the names differ.
+//
+// Output is written to one <resource>_gen.go file per resource. Any function, method or type that is
+// already declared in a hand-written file of the target package is left out of the generated code,
+// so hand-written overrides survive regeneration.
+//
+// It is invoked via go:generate directives in the loader and resolver packages:
+//
+//	go generate ./loader ./resolver
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		kind = flag.String("kind", "", "what to generate: loader or resolver")
+		dir  = flag.String("dir", ".", "the package directory to write generated files into")
+	)
+
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("swapigen: ")
+
+	tmpl, ok := templates[*kind]
+	if !ok {
+		log.Fatalf("unknown -kind %q: must be loader or resolver", *kind)
+	}
+
+	root, err := moduleRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resources, err := loadResources(filepath.Join(root, "swapi"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	declared, err := declarations(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, r := range resources {
+		src, err := render(tmpl, r, declared)
+		if err != nil {
+			log.Fatalf("generating %s %s: %s", *kind, r.Type, err)
+		}
+
+		path := filepath.Join(*dir, r.File+"_gen.go")
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *kind == "resolver" {
+		for _, r := range resources {
+			for _, f := range r.HandWritten {
+				fmt.Fprintf(os.Stderr, "swapigen: %s.%s must be resolved by hand\n", r.Type, f)
+			}
+		}
+	}
+}
+
+// moduleRoot walks up from the working directory to the directory containing go.mod.
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found above the working directory")
+		}
+
+		dir = parent
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeneratedCodeIsCurrent fails when the checked-in _gen.go files differ from what the
+// generator produces, i.e. when someone forgot to run go generate.
+func TestGeneratedCodeIsCurrent(t *testing.T) {
+	root, err := moduleRoot()
+	require.NoError(t, err)
+
+	resources, err := loadResources(filepath.Join(root, "swapi"))
+	require.NoError(t, err)
+
+	for kind, tmpl := range templates {
+		dir := filepath.Join(root, kind)
+
+		declared, err := declarations(dir)
+		require.NoError(t, err)
+
+		for _, r := range resources {
+			want, err := render(tmpl, r, declared)
+			require.NoError(t, err)
+
+			got, err := os.ReadFile(filepath.Join(dir, r.File+"_gen.go"))
+			require.NoError(t, err)
+			require.Equal(t, string(want), string(got), "%s/%s_gen.go is stale: run go generate ./...", kind, r.File)
+		}
+	}
+}
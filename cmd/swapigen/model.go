@@ -0,0 +1,286 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/introspection"
+
+	"github.com/tonyghita/graphql-go-example/schema"
+)
+
+//go:embed mapping.json
+var mapping []byte
+
+// A resource is a SWAPI resource exposed as a GraphQL object type.
+type resource struct {
+	Type              string            `json:"type"`              // The GraphQL type and swapi struct name, such as "Film".
+	Plural            string            `json:"plural"`            // The plural used by loaders, such as "Films".
+	ConstructorPlural string            `json:"constructorPlural"` // The plural used by resolver constructors, when different.
+	Var               string            `json:"var"`               // The resolver struct field holding the swapi value.
+	Page              string            `json:"page"`              // The swapi page field holding the results.
+	File              string            `json:"file"`              // The generated file name, without the _gen.go suffix.
+	Fields            map[string]string `json:"fields"`            // Schema field names mapped to swapi struct field names.
+
+	Methods     []method // The resolver methods that can be generated.
+	HandWritten []string // The schema fields that must be resolved by hand.
+}
+
+// A method is a generated resolver method for a single schema field.
+type method struct {
+	Name   string    // The Go method name, such as "Characters".
+	Field  string    // The schema field name, such as "characters".
+	Kind   string    // How the field is resolved; see resolveKind.
+	Source string    // The swapi struct field the value is read from.
+	Target *resource // The related resource, for relationship fields.
+}
+
+// A structField is a field of a swapi struct.
+type structField struct {
+	Name string // The Go field name.
+	Type string // The Go type, such as "string" or "[]string".
+}
+
+// loadResources reads mapping.json and matches every resource's schema fields to its swapi struct.
+func loadResources(swapiDir string) ([]*resource, error) {
+	var resources []*resource
+	if err := json.Unmarshal(mapping, &resources); err != nil {
+		return nil, fmt.Errorf("parsing mapping.json: %w", err)
+	}
+
+	structs, err := parseStructs(swapiDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := schema.String()
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]*introspection.Type)
+	for _, t := range graphql.MustParseSchema(s, nil, graphql.UseStringDescriptions()).Inspect().Types() {
+		types[*t.Name()] = t
+	}
+
+	byType := make(map[string]*resource, len(resources))
+	for _, r := range resources {
+		if r.ConstructorPlural == "" {
+			r.ConstructorPlural = r.Plural
+		}
+
+		byType[r.Type] = r
+	}
+
+	for _, r := range resources {
+		typ, ok := types[r.Type]
+		if !ok {
+			return nil, fmt.Errorf("type %s is not in the schema", r.Type)
+		}
+
+		fields, ok := structs[r.Type]
+		if !ok {
+			return nil, fmt.Errorf("struct swapi.%s does not exist", r.Type)
+		}
+
+		for _, f := range *typ.Fields(&struct{ IncludeDeprecated bool }{true}) {
+			m, ok := resolveKind(r, f, fields, byType)
+			if !ok {
+				r.HandWritten = append(r.HandWritten, f.Name())
+				continue
+			}
+
+			r.Methods = append(r.Methods, m)
+		}
+	}
+
+	return resources, nil
+}
+
+// resolveKind determines whether a schema field can be generated and how it is resolved:
+//
+//	id              the identifier, extracted from the resource URL
+//	string          a non-null String read from a string
+//	nullableString  a nullable String read from a string, null when empty
+//	split           a list of Strings read from a comma-separated string
+//	time            a non-null Time read from an RFC3339 string
+//	nullableTime    a nullable Time read from an RFC3339 string, null when empty
+//	one             a related resource read from a URL
+//	many            a list of related resources read from a list of URLs
+func resolveKind(r *resource, f *introspection.Field, fields map[string]structField, resources map[string]*resource) (method, bool) {
+	m := method{Name: exported(f.Name()), Field: f.Name()}
+
+	if f.Name() == "id" {
+		m.Kind = "id"
+		return m, true
+	}
+
+	if len(f.Args()) > 0 {
+		return m, false
+	}
+
+	src, ok := sourceField(r, f.Name(), fields)
+	if !ok {
+		return m, false
+	}
+
+	m.Source = src.Name
+	nonNull, list, named := unwrap(f.Type())
+
+	switch {
+	case src.Type == "string" && named == "String" && list:
+		m.Kind = "split"
+	case src.Type == "string" && named == "String" && nonNull:
+		m.Kind = "string"
+	case src.Type == "string" && named == "String":
+		m.Kind = "nullableString"
+	case src.Type == "string" && named == "Time" && nonNull:
+		m.Kind = "time"
+	case src.Type == "string" && named == "Time":
+		m.Kind = "nullableTime"
+	case src.Type == "string" && resources[named] != nil && !list && !nonNull:
+		m.Kind, m.Target = "one", resources[named]
+	case src.Type == "[]string" && resources[named] != nil && list && !nonNull:
+		m.Kind, m.Target = "many", resources[named]
+	default:
+		return m, false
+	}
+
+	return m, true
+}
+
+// commonFields are the swapi JSON attributes of schema fields shared by every resource.
+var commonFields = map[string]string{
+	"createdAt": "created",
+	"editedAt":  "edited",
+}
+
+// sourceField finds the swapi struct field for a schema field. An explicit entry in mapping.json
+// wins; otherwise the field whose camel-cased JSON attribute matches the schema field is used.
+func sourceField(r *resource, field string, fields map[string]structField) (structField, bool) {
+	if name, ok := r.Fields[field]; ok {
+		for _, f := range fields {
+			if f.Name == name {
+				return f, true
+			}
+		}
+
+		return structField{}, false
+	}
+
+	if attr, ok := commonFields[field]; ok {
+		f, ok := fields[attr]
+		return f, ok
+	}
+
+	for attr, f := range fields {
+		if camelCase(attr) == field {
+			return f, true
+		}
+	}
+
+	return structField{}, false
+}
+
+// unwrap reports whether a schema type is non-null and a list, along with its named type.
+func unwrap(t *introspection.Type) (nonNull, list bool, named string) {
+	if t.Kind() == "NON_NULL" {
+		nonNull, t = true, t.OfType()
+	}
+
+	if t.Kind() == "LIST" {
+		list, t = true, t.OfType()
+	}
+
+	for t.OfType() != nil {
+		t = t.OfType()
+	}
+
+	return nonNull, list, *t.Name()
+}
+
+// parseStructs reads the struct types declared in the swapi package, keyed by JSON attribute.
+func parseStructs(dir string) (map[string]map[string]structField, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing swapi package: %w", err)
+	}
+
+	structs := make(map[string]map[string]structField)
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					return false
+				}
+
+				fields := make(map[string]structField)
+				for _, f := range st.Fields.List {
+					if f.Tag == nil || len(f.Names) != 1 {
+						continue
+					}
+
+					tag, err := strconv.Unquote(f.Tag.Value)
+					if err != nil {
+						continue
+					}
+
+					attr := strings.Split(reflect.StructTag(tag).Get("json"), ",")[0]
+					fields[attr] = structField{Name: f.Names[0].Name, Type: typeString(f.Type)}
+				}
+
+				structs[spec.Name.Name] = fields
+				return false
+			})
+		}
+	}
+
+	return structs, nil
+}
+
+func typeString(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	default:
+		return ""
+	}
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = exported(parts[i])
+	}
+
+	return strings.Join(parts, "")
+}
+
+func exported(s string) string {
+	if s == "id" {
+		return "ID"
+	}
+
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// declarations collects the names of everything declared in the hand-written Go files of a
+// package directory. Functions and types are recorded by name and methods as "Type.Method".
+//
+// Generated files and tests are ignored, so regenerating never mistakes its own output for an
+// override.
+func declarations(dir string) (map[string]bool, error) {
+	declared := make(map[string]bool)
+
+	skip := func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_gen.go") && !strings.HasSuffix(name, "_test.go")
+	}
+
+	pkgs, err := parser.ParseDir(token.NewFileSet(), filepath.Clean(dir), skip, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					if d.Recv == nil {
+						declared[d.Name.Name] = true
+						continue
+					}
+
+					declared[receiver(d.Recv.List[0].Type)+"."+d.Name.Name] = true
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						if ts, ok := spec.(*ast.TypeSpec); ok {
+							declared[ts.Name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return declared, nil
+}
+
+// receiver returns the type name of a method receiver, without any pointer.
+func receiver(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		return receiver(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return ""
+	}
+}
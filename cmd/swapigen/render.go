@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// header marks generated files, as recognized by go vet and most editors.
+const header = "// Code generated by swapigen. DO NOT EDIT.\n\n"
+
+// packages are the imports generated code may use, keyed by the identifier they are referred to by.
+var packages = []struct{ name, path string }{
+	{"context", "context"},
+	{"strings", "strings"},
+	{"sync", "sync"},
+	{"time", "time"},
+	{"graphql", "github.com/graph-gophers/graphql-go"},
+	{"errors", "github.com/tonyghita/graphql-go-example/errors"},
+	{"loader", "github.com/tonyghita/graphql-go-example/loader"},
+	{"normalize", "github.com/tonyghita/graphql-go-example/normalize"},
+	{"swapi", "github.com/tonyghita/graphql-go-example/swapi"},
+}
+
+// render executes the template for a resource and formats the result, adding a package clause
+// and only the imports the generated code refers to.
+func render(tmpl *template.Template, r *resource, declared map[string]bool) ([]byte, error) {
+	var body bytes.Buffer
+
+	data := struct {
+		*resource
+		Declared map[string]bool
+	}{r, declared}
+
+	if err := tmpl.Execute(&body, data); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	fmt.Fprintf(&buf, "package %s\n\n", tmpl.Name())
+
+	// Imports are grouped as standard library, third party, then this module.
+	groups := make([][]string, 3)
+	for _, p := range packages {
+		if !regexp.MustCompile(`\b` + p.name + `\.`).Match(body.Bytes()) {
+			continue
+		}
+
+		g := 0
+		switch {
+		case strings.HasPrefix(p.path, "github.com/tonyghita/"):
+			g = 2
+		case strings.Contains(p.path, "."):
+			g = 1
+		}
+
+		groups[g] = append(groups[g], fmt.Sprintf("%q", p.path))
+	}
+
+	var imports []string
+	for _, g := range groups {
+		if len(g) > 0 {
+			imports = append(imports, strings.Join(g, "\n"))
+		}
+	}
+
+	if len(imports) > 0 {
+		fmt.Fprintf(&buf, "import (\n%s\n)\n\n", strings.Join(imports, "\n\n"))
+	}
+
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.String())
+	}
+
+	return src, nil
+}
+
+var funcs = template.FuncMap{
+	"lower": func(s string) string {
+		return strings.ToLower(s[:1]) + s[1:]
+	},
+	"words": func(s string) string {
+		return strings.ToLower(s)
+	},
+	"exported": exported,
+}
@@ -0,0 +1,225 @@
+package main
+
+import "text/template"
+
+// templates are keyed by the -kind flag. Each template is named after the package it generates.
+var templates = map[string]*template.Template{
+	"loader":   template.Must(template.New("loader").Funcs(funcs).Parse(loaderTemplate)),
+	"resolver": template.Must(template.New("resolver").Funcs(funcs).Parse(resolverTemplate)),
+}
+
+const loaderTemplate = `
+{{- $t := .Type}}{{$p := .Plural}}{{$v := lower .Type}}{{$d := .Declared}}
+{{- if not (index $d (printf "Load%s" $t))}}
+// Load{{$t}} loads a {{words $t}} resource from the SWAPI API URL.
+func Load{{$t}}(ctx context.Context, url string) (swapi.{{$t}}, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return swapi.{{$t}}{}, err
+	}
+
+	return ldrs.{{$v}}.Load(ctx, url)
+}
+{{end}}
+{{- if not (index $d (printf "Prime%s" $p))}}
+// Prime{{$p}} primes the {{words $t}} loader with the resources in a search results page.
+func Prime{{$p}}(ctx context.Context, page swapi.{{$t}}Page) error {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range page.{{.Page}} {
+		ldrs.{{$v}}.Prime(ctx, v.URL, v)
+	}
+
+	return nil
+}
+{{end}}
+{{- if not (index $d (printf "%sGetter" $v))}}
+type {{$v}}Getter interface {
+	{{$t}}(ctx context.Context, url string) (swapi.{{$t}}, error)
+}
+{{end}}
+{{- if not (index $d (printf "%sLoader" $v))}}
+// {{$v}}Loader contains the client required to load {{words $t}} resources.
+type {{$v}}Loader struct {
+	get {{$v}}Getter
+}
+{{end}}
+{{- if not (index $d (printf "new%sLoader" $t))}}
+func new{{$t}}Loader(client {{$v}}Getter) BatchFunc[string, swapi.{{$t}}] {
+	return {{$v}}Loader{get: client}.loadBatch
+}
+{{end}}
+{{- if not (index $d (printf "%sLoader.loadBatch" $v))}}
+func (ldr {{$v}}Loader) loadBatch(ctx context.Context, urls []string) []Result[swapi.{{$t}}] {
+	var (
+		n       = len(urls)
+		results = make([]Result[swapi.{{$t}}], n)
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(n)
+
+	for i, url := range urls {
+		go func(i int, url string) {
+			defer wg.Done()
+
+			data, err := ldr.get.{{$t}}(ctx, url)
+			results[i] = Result[swapi.{{$t}}]{Value: data, Error: err}
+		}(i, url)
+	}
+
+	wg.Wait()
+
+	return results
+}
+{{end}}`
+
+const resolverTemplate = `
+{{- $t := .Type}}{{$cp := .ConstructorPlural}}{{$v := .Var}}{{$a := exported .Var}}{{$d := .Declared}}
+{{- if not (index $d (printf "%sResolver" $t))}}
+// {{$t}}Resolver resolves the {{$t}} type.
+// The {{words $t}} is only loaded once a field other than the ID is resolved.
+type {{$t}}Resolver struct {
+	{{$v}} *lazy[swapi.{{$t}}]
+}
+{{end}}
+{{- if not (index $d (printf "New%sArgs" $t))}}
+// New{{$t}}Args are the arguments for New{{$t}}.
+// The {{words $t}} is loaded from URL unless {{$a}} is given.
+type New{{$t}}Args struct {
+	{{$a}} swapi.{{$t}}
+	URL string
+}
+{{end}}
+{{- if not (index $d (printf "New%sArgs" $cp))}}
+// New{{$cp}}Args are the arguments for New{{$cp}}.
+type New{{$cp}}Args struct {
+	Page swapi.{{$t}}Page
+	URLs []string
+}
+{{end}}
+{{- if not (index $d (printf "New%s" $t))}}
+// New{{$t}} creates a resolver for a single {{words $t}}.
+func New{{$t}}(ctx context.Context, args New{{$t}}Args) (*{{$t}}Resolver, error) {
+	switch {
+	case args.{{$a}}.URL != "":
+		return &{{$t}}Resolver{ {{- $v}}: loaded(args.{{$a}}.URL, args.{{$a}})}, nil
+	case args.URL != "":
+		return &{{$t}}Resolver{ {{- $v}}: deferred(args.URL, loader.Load{{$t}})}, nil
+	default:
+		return nil, errors.UnableToResolve
+	}
+}
+{{end}}
+{{- if not (index $d (printf "New%s" $cp))}}
+// New{{$cp}} creates resolvers for a list of URLs followed by a search results page.
+//
+// Each resolver keeps the position of its URL. The resources are loaded lazily, so a resource which
+// fails to load only fails the fields resolved on it, and the errors carry the resource's index.
+func New{{$cp}}(ctx context.Context, args New{{$cp}}Args) (*[]*{{$t}}Resolver, error) {
+	err := loader.Prime{{.Plural}}(ctx, args.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*{{$t}}Resolver, 0, len(args.URLs)+len(args.Page.{{.Page}}))
+
+	for _, url := range args.URLs {
+		resolvers = append(resolvers, &{{$t}}Resolver{ {{- $v}}: deferred(url, loader.Load{{$t}})})
+	}
+
+	for _, v := range args.Page.{{.Page}} {
+		resolvers = append(resolvers, &{{$t}}Resolver{ {{- $v}}: loaded(v.URL, v)})
+	}
+
+	return &resolvers, nil
+}
+{{end}}
+{{- if not (index $d (printf "%sResolver.field" $t))}}
+// field identifies one of this {{words $t}}'s attributes for data-quality warnings.
+func (r *{{$t}}Resolver) field(name string) normalize.Field {
+	return normalize.Field{Type: "{{$t}}", ID: string(r.ID()), Name: name}
+}
+{{end}}
+{{- range .Methods}}
+{{- if not (index $d (printf "%sResolver.%s" $t .Name))}}
+// {{.Name}} resolves the {{.Field}} field.
+{{- if eq .Kind "id"}}
+func (r *{{$t}}Resolver) ID() graphql.ID {
+	return extractID(r.{{$v}}.url)
+}
+{{- else if eq .Kind "string"}}
+func (r *{{$t}}Resolver) {{.Name}}(ctx context.Context) (string, error) {
+	{{$v}}, err := r.{{$v}}.get(ctx)
+	return {{$v}}.{{.Source}}, err
+}
+{{- else if eq .Kind "nullableString"}}
+func (r *{{$t}}Resolver) {{.Name}}(ctx context.Context) (*string, error) {
+	{{$v}}, err := r.{{$v}}.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nullableStr({{$v}}.{{.Source}}), nil
+}
+{{- else if eq .Kind "split"}}
+func (r *{{$t}}Resolver) {{.Name}}(ctx context.Context) ([]string, error) {
+	{{$v}}, err := r.{{$v}}.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList({{$v}}.{{.Source}}), nil
+}
+{{- else if eq .Kind "time"}}
+func (r *{{$t}}Resolver) {{.Name}}(ctx context.Context) (graphql.Time, error) {
+	{{$v}}, err := r.{{$v}}.get(ctx)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, {{$v}}.{{.Source}})
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	return graphql.Time{Time: t}, nil
+}
+{{- else if eq .Kind "nullableTime"}}
+func (r *{{$t}}Resolver) {{.Name}}(ctx context.Context) (*graphql.Time, error) {
+	{{$v}}, err := r.{{$v}}.get(ctx)
+	if err != nil || {{$v}}.{{.Source}} == "" {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, {{$v}}.{{.Source}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphql.Time{Time: t}, nil
+}
+{{- else if eq .Kind "one"}}
+func (r *{{$t}}Resolver) {{.Name}}(ctx context.Context) (*{{.Target.Type}}Resolver, error) {
+	{{$v}}, err := r.{{$v}}.get(ctx)
+	if err != nil || {{$v}}.{{.Source}} == "" {
+		return nil, err
+	}
+
+	return New{{.Target.Type}}(ctx, New{{.Target.Type}}Args{URL: {{$v}}.{{.Source}}})
+}
+{{- else if eq .Kind "many"}}
+func (r *{{$t}}Resolver) {{.Name}}(ctx context.Context) (*[]*{{.Target.Type}}Resolver, error) {
+	{{$v}}, err := r.{{$v}}.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return New{{.Target.ConstructorPlural}}(ctx, New{{.Target.ConstructorPlural}}Args{URLs: {{$v}}.{{.Source}}})
+}
+{{- end}}
+{{end}}
+{{- end}}`
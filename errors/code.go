@@ -0,0 +1,181 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+
+	graphql "github.com/graph-gophers/graphql-go/errors"
+)
+
+// A Code classifies an error for API clients. It is reported in the extensions.code field of
+// every error in a response.
+type Code string
+
+const (
+	// NotFound means a requested resource does not exist.
+	NotFound Code = "NOT_FOUND"
+	// UpstreamUnavailable means the SWAPI REST API could not be reached or failed to respond.
+	UpstreamUnavailable Code = "UPSTREAM_UNAVAILABLE"
+	// BadUserInput means the query or its arguments are invalid.
+	BadUserInput Code = "BAD_USER_INPUT"
+	// Forbidden means the principal the request was authenticated as may not access a field.
+	Forbidden Code = "FORBIDDEN"
+	// Canceled means the work was abandoned because the client went away, such as when it closed
+	// the connection before the response was ready.
+	Canceled Code = "CANCELED"
+	// Internal means anything else. The message of an internal error is not meant for clients.
+	Internal Code = "INTERNAL"
+)
+
+// publicMessages are the messages shown to clients in place of the original message when errors
+// are masked. Errors caused by user input are never masked, since the client needs the details.
+var publicMessages = map[Code]string{
+	NotFound:            "resource not found",
+	UpstreamUnavailable: "upstream service unavailable",
+	Internal:            "internal server error",
+}
+
+type codedError struct {
+	cause error
+	code  Code
+}
+
+// WithCode attaches a Code to the error.
+// The code can be retrieved with CodeOf.
+func WithCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+
+	return codedError{cause: err, code: code}
+}
+
+// Error implements the `error` interface.
+func (e codedError) Error() string {
+	return e.cause.Error()
+}
+
+// Cause returns the contained error.
+func (e codedError) Cause() error {
+	return e.cause
+}
+
+// Unwrap allows the standard library errors package to inspect the contained error.
+func (e codedError) Unwrap() error {
+	return e.cause
+}
+
+// Errors may also be classified by asserting these behaviors, so packages such as swapi can
+// describe their errors without importing this package.
+type (
+	notFounder interface {
+		NotFound() bool
+	}
+
+	unavailabler interface {
+		Unavailable() bool
+	}
+	forbidder interface {
+		Forbidden() bool
+	}
+)
+
+type causer interface {
+	Cause() error
+}
+
+// CodeOf classifies an error.
+// It returns the first code found on the error or any error it wraps, and Internal otherwise.
+func CodeOf(err error) Code {
+	for err != nil {
+		if c, ok := err.(codedError); ok {
+			return c.code
+		}
+
+		// A call abandoned because its caller went away is not an upstream failure, even though
+		// the swapi package reports it as one.
+		if stderrors.Is(err, context.Canceled) {
+			return Canceled
+		}
+
+		if nf, ok := err.(notFounder); ok && nf.NotFound() {
+			return NotFound
+		}
+
+		if u, ok := err.(unavailabler); ok && u.Unavailable() {
+			return UpstreamUnavailable
+		}
+
+		if f, ok := err.(forbidder); ok && f.Forbidden() {
+			return Forbidden
+		}
+
+		if stderrors.Is(err, context.DeadlineExceeded) {
+			return UpstreamUnavailable
+		}
+
+		if c, ok := err.(causer); ok {
+			err = c.Cause()
+		} else {
+			err = stderrors.Unwrap(err)
+		}
+	}
+
+	return Internal
+}
+
+// Classify determines the Code of a query error.
+//
+// Errors returned by resolvers are classified with CodeOf. Errors without a resolver error are
+// raised by graphql-go itself: those without a path are parse, validation and variable errors,
+// which are caused by user input, and those with a path are execution failures such as panics.
+func Classify(err *graphql.QueryError) Code {
+	switch {
+	case err.ResolverError != nil:
+		return CodeOf(err.ResolverError)
+	case len(err.Path) == 0:
+		return BadUserInput
+	default:
+		return Internal
+	}
+}
+
+// Annotate sets extensions.code and extensions.correlationId on every error.
+// The correlation ID ties the errors in a response to the server logs for the request.
+func Annotate(errs []*graphql.QueryError, correlationID string) {
+	for _, err := range errs {
+		if err.Extensions == nil {
+			err.Extensions = make(map[string]interface{}, 2)
+		}
+
+		err.Extensions["code"] = Classify(err)
+
+		if correlationID != "" {
+			err.Extensions["correlationId"] = correlationID
+		}
+	}
+}
+
+// Mask replaces the message of every error which is not caused by user input with generic text,
+// so internal details such as upstream URLs and parse failures are not exposed to clients.
+// It returns the errors whose messages were replaced, with their original messages, so they can be
+// logged.
+func Mask(errs []*graphql.QueryError) []*graphql.QueryError {
+	var masked []*graphql.QueryError
+
+	for _, err := range errs {
+		code := Classify(err)
+
+		msg, ok := publicMessages[code]
+		if !ok || err.Message == msg {
+			continue
+		}
+
+		original := *err
+		masked = append(masked, &original)
+
+		err.Message = msg
+	}
+
+	return masked
+}
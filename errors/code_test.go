@@ -0,0 +1,86 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+func TestCodeOf(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want errors.Code
+	}{
+		{"plain", errors.New("boom"), errors.Internal},
+		{"coded", errors.WithCode(errors.New("bad unit"), errors.BadUserInput), errors.BadUserInput},
+		{"indexed", errors.WithIndex(errors.WithCode(errors.New("x"), errors.NotFound), 2), errors.NotFound},
+		{"not found", &swapi.StatusError{StatusCode: 404}, errors.NotFound},
+		{"server error", &swapi.StatusError{StatusCode: 502}, errors.UpstreamUnavailable},
+		{"bad request", &swapi.StatusError{StatusCode: 400}, errors.Internal},
+		{"wrapped", fmt.Errorf("loading: %w", &swapi.StatusError{StatusCode: 503}), errors.UpstreamUnavailable},
+		{"deadline", fmt.Errorf("loading: %w", context.DeadlineExceeded), errors.UpstreamUnavailable},
+		{"canceled", fmt.Errorf("loading: %w", context.Canceled), errors.Canceled},
+		{"canceled indexed", errors.WithIndex(fmt.Errorf("loading: %w", context.Canceled), 1), errors.Canceled},
+		{"forbidden", &auth.ForbiddenError{Coordinate: "Starship.cost"}, errors.Forbidden},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want, errors.CodeOf(c.err))
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	errs := []*graphql.QueryError{
+		{Message: "syntax error"},
+		{Message: "panic occurred", Path: []interface{}{"films", 0}},
+		{Message: "GET https://swapi.dev/api/people/0/: 404 Not Found", Path: []interface{}{"person"}, ResolverError: &swapi.StatusError{StatusCode: 404}},
+	}
+
+	errors.Annotate(errs, "abc")
+
+	require.Equal(t, errors.BadUserInput, errs[0].Extensions["code"])
+	require.Equal(t, errors.Internal, errs[1].Extensions["code"])
+	require.Equal(t, errors.NotFound, errs[2].Extensions["code"])
+
+	for _, err := range errs {
+		require.Equal(t, "abc", err.Extensions["correlationId"])
+	}
+}
+
+func TestMask(t *testing.T) {
+	errs := []*graphql.QueryError{
+		{Message: "Unknown argument \"foo\"."},
+		{Message: "unable to parse JSON [GET /api/films/]", Path: []interface{}{"films"}, ResolverError: errors.New("unable to parse JSON [GET /api/films/]")},
+		{Message: "GET https://swapi.dev/api/people/0/: 404 Not Found", Path: []interface{}{"person"}, ResolverError: &swapi.StatusError{StatusCode: 404}},
+	}
+
+	masked := errors.Mask(errs)
+
+	require.Equal(t, "Unknown argument \"foo\".", errs[0].Message)
+	require.Equal(t, "internal server error", errs[1].Message)
+	require.Equal(t, "resource not found", errs[2].Message)
+
+	require.Len(t, masked, 2)
+	require.Equal(t, "unable to parse JSON [GET /api/films/]", masked[0].Message)
+	require.Equal(t, "GET https://swapi.dev/api/people/0/: 404 Not Found", masked[1].Message)
+}
+
+func TestCodeOfCanceledCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The client reports the abandoned call as unavailable; it is classified as canceled.
+	_, err := swapi.NewClient(nil).Film(ctx, "https://swapi.dev/api/films/1/")
+	require.Error(t, err)
+	require.Equal(t, errors.Canceled, errors.CodeOf(err))
+}
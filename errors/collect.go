@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"context"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go/errors"
+)
+
+// A Collector gathers the errors resolvers report without failing their field.
+//
+// graphql-go discards the value of a field whose resolver returns an error, so a list which
+// resolves some of its items cannot fail the others that way. Its resolver reports their errors on
+// the Collector instead, and the executor of the operation appends them to the response.
+// It is safe for concurrent use.
+type Collector struct {
+	mu   sync.Mutex
+	errs []*graphql.QueryError
+}
+
+// The collectorKey type is unexported so the collector does not collide with context values set by
+// other packages.
+type collectorKey struct{}
+
+// Collect places a new Collector on the context.
+func Collect(ctx context.Context) (context.Context, *Collector) {
+	c := &Collector{}
+	return context.WithValue(ctx, collectorKey{}, c), c
+}
+
+// Report records the error of the field at the path, such as "_entities". Errors attached to
+// list items with WithIndex are expanded like the errors resolvers return. Paths name fields
+// rather than their aliases. Report does nothing when the context has no Collector.
+func Report(ctx context.Context, err error, path ...interface{}) {
+	c, ok := ctx.Value(collectorKey{}).(*Collector)
+	if !ok || err == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errs = append(c.errs, &graphql.QueryError{Message: err.Error(), Path: path, ResolverError: err})
+}
+
+// Errors returns the errors reported so far, in the order they were reported.
+func (c *Collector) Errors() []*graphql.QueryError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*graphql.QueryError{}, c.errs...)
+}
@@ -0,0 +1,29 @@
+package errors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+)
+
+func TestCollect(t *testing.T) {
+	// Without a collector, errors are dropped.
+	errors.Report(context.Background(), errors.New("dropped"), "films")
+
+	ctx, c := errors.Collect(context.Background())
+	errors.Report(ctx, nil, "films")
+	errors.Report(ctx, errors.Errors{
+		errors.WithIndex(errors.WithCode(errors.New("bad 1"), errors.BadUserInput), 1),
+		errors.WithIndex(errors.New("bad 3"), 3),
+	}, "_entities")
+
+	errs := errors.Expand(c.Errors())
+	require.Len(t, errs, 2)
+	require.Equal(t, "bad 1", errs[0].Message)
+	require.Equal(t, []interface{}{"_entities", 1}, errs[0].Path)
+	require.Equal(t, errors.BadUserInput, errors.Classify(errs[0]))
+	require.Equal(t, []interface{}{"_entities", 3}, errs[1].Path)
+}
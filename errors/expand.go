@@ -11,26 +11,18 @@ type indexedCauser interface {
 	Cause() error
 }
 
+// Expand replaces every query error caused by a resolver returning more than one error with one
+// query error per contained error.
+//
+// Errors are expanded recursively: a list of errors may contain further lists, and each index
+// attached with WithIndex is appended to the path of the error it contains.
 func Expand(errs []*graphql.QueryError) []*graphql.QueryError {
 	expanded := make([]*graphql.QueryError, 0, len(errs))
 
 	for _, err := range errs {
-		switch t := err.ResolverError.(type) {
-		case slicer:
-			for _, e := range t.Slice() {
-				qe := &graphql.QueryError{
-					Message:   err.Message,
-					Locations: err.Locations,
-					Path:      err.Path,
-				}
-
-				if ic, ok := e.(indexedCauser); ok {
-					qe.Path = append(qe.Path, ic.Index())
-					qe.Message = ic.Cause().Error()
-				}
-
-				expanded = append(expanded, qe)
-			}
+		switch err.ResolverError.(type) {
+		case slicer, indexedCauser:
+			expanded = expand(expanded, err, err.ResolverError, err.Path)
 		default:
 			expanded = append(expanded, err)
 		}
@@ -38,3 +30,41 @@ func Expand(errs []*graphql.QueryError) []*graphql.QueryError {
 
 	return expanded
 }
+
+func expand(expanded []*graphql.QueryError, parent *graphql.QueryError, err error, path []interface{}) []*graphql.QueryError {
+	switch t := err.(type) {
+	case slicer:
+		for _, e := range t.Slice() {
+			expanded = expand(expanded, parent, e, path)
+		}
+
+		return expanded
+	case indexedCauser:
+		// Copy the path so sibling errors do not share a backing array.
+		p := make([]interface{}, len(path), len(path)+1)
+		copy(p, path)
+
+		return expand(expanded, parent, t.Cause(), append(p, t.Index()))
+	default:
+		return append(expanded, &graphql.QueryError{
+			Message:       err.Error(),
+			Locations:     parent.Locations,
+			Path:          path,
+			ResolverError: err,
+			Extensions:    copyExtensions(parent.Extensions),
+		})
+	}
+}
+
+func copyExtensions(ext map[string]interface{}) map[string]interface{} {
+	if ext == nil {
+		return nil
+	}
+
+	c := make(map[string]interface{}, len(ext))
+	for k, v := range ext {
+		c[k] = v
+	}
+
+	return c
+}
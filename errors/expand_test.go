@@ -0,0 +1,38 @@
+package errors_test
+
+import (
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+)
+
+func TestExpand(t *testing.T) {
+	nested := errors.Errors{
+		errors.WithIndex(errors.Errors{
+			errors.WithIndex(errors.New("inner 0"), 0),
+			errors.WithIndex(errors.New("inner 3"), 3),
+		}, 1),
+		errors.WithIndex(errors.New("outer 2"), 2),
+	}
+
+	errs := errors.Expand([]*graphql.QueryError{
+		{Message: "plain", Path: []interface{}{"a"}},
+		{Message: nested.Error(), Path: []interface{}{"films"}, ResolverError: nested},
+	})
+
+	require.Len(t, errs, 4)
+	require.Equal(t, "plain", errs[0].Message)
+
+	require.Equal(t, "inner 0", errs[1].Message)
+	require.Equal(t, []interface{}{"films", 1, 0}, errs[1].Path)
+
+	require.Equal(t, "inner 3", errs[2].Message)
+	require.Equal(t, []interface{}{"films", 1, 3}, errs[2].Path)
+
+	require.Equal(t, "outer 2", errs[3].Message)
+	require.Equal(t, []interface{}{"films", 2}, errs[3].Path)
+	require.NotNil(t, errs[3].ResolverError)
+}
@@ -1,10 +1,16 @@
 module github.com/tonyghita/graphql-go-example
 
-go 1.16
+go 1.18
 
 require (
 	github.com/graph-gophers/dataloader v5.0.0+incompatible
 	github.com/graph-gophers/graphql-go v0.0.0-20210306090651-bd703c223f03
-	github.com/opentracing/opentracing-go v1.2.0 // indirect
 	github.com/stretchr/testify v1.7.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/opentracing/opentracing-go v1.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+)
+
+// authenticate returns the principal the request was sent by, or nil for an anonymous request.
+// When the request may not proceed, it responds with 401 Unauthorized and returns false.
+//
+// Every failed attempt is spent from the request budget of the client's IP address, so credentials
+// cannot be guessed faster than anonymous clients may send requests. Once that budget is spent,
+// it responds with 429 Too Many Requests instead.
+func (h GraphQL) authenticate(w http.ResponseWriter, r *http.Request, id string) (*auth.Principal, bool) {
+	if h.Authenticator == nil {
+		return nil, true
+	}
+
+	p, err := h.Authenticator.Authenticate(r)
+
+	switch {
+	case err == nil:
+		return p, true
+	case errors.Is(err, auth.ErrNoCredentials) && !h.RequireAuthentication:
+		return nil, true
+	}
+
+	if !h.rateLimitFailure(w, r, id) {
+		return nil, false
+	}
+
+	if errors.Is(err, auth.ErrNoCredentials) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="graphql"`)
+		respond(w, errorJSON("authentication required"), http.StatusUnauthorized)
+
+		return nil, false
+	}
+
+	// The reason is only logged, since it may help an attacker forge credentials.
+	h.logf("[%s] authentication failed: %s", id, err)
+	w.Header().Set("WWW-Authenticate", `Bearer realm="graphql", error="invalid_token"`)
+	respond(w, errorJSON("invalid credentials"), http.StatusUnauthorized)
+
+	return nil, false
+}
+
+// authorizeAndExec executes the operation, unless it selects a root field the principal may not
+// access, in which case it fails before anything is executed. Fields the principal may not access
+// are removed from introspection results when HideForbiddenFields is set.
+func (h GraphQL) authorizeAndExec(ctx context.Context, q query) *graphql.Response {
+	if h.Authorization == nil {
+		return h.Schema.Exec(ctx, q.Query, q.OpName, q.Variables)
+	}
+
+	p, _ := auth.FromContext(ctx)
+
+	if errs := h.Authorization.CheckRoot(p, q.Query, q.OpName, q.Variables); len(errs) > 0 {
+		return &graphql.Response{Errors: errs}
+	}
+
+	if !h.HideForbiddenFields {
+		return h.Schema.Exec(ctx, q.Query, q.OpName, q.Variables)
+	}
+
+	prepared, introspects := h.Authorization.PrepareIntrospection(q.Query)
+
+	res := h.Schema.Exec(ctx, prepared, q.OpName, q.Variables)
+	if !introspects || len(res.Data) == 0 {
+		return res
+	}
+
+	data, err := h.Authorization.FilterIntrospection(p, res.Data)
+	if err != nil {
+		// Never return an unfiltered result.
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{{Message: err.Error(), ResolverError: err}}}
+	}
+
+	res.Data = data
+
+	return res
+}
@@ -0,0 +1,211 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/handler"
+)
+
+const authSchema = `
+schema { query: Query }
+
+type Query {
+	viewer: String
+}
+`
+
+type authRoot struct{}
+
+// Viewer reads the principal the handler placed on the context.
+func (authRoot) Viewer(ctx context.Context) *string {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	return &p.Subject
+}
+
+func TestAuthentication(t *testing.T) {
+	keys, err := auth.ParseAPIKeys(strings.NewReader("secret-key mobile-app"))
+	require.NoError(t, err)
+
+	post := func(h handler.GraphQL, key string) (*http.Response, string) {
+		ts := httptest.NewServer(h)
+		defer ts.Close()
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"query": "{ viewer }"}`))
+		require.NoError(t, err)
+
+		if key != "" {
+			req.Header.Set(auth.APIKeyHeader, key)
+		}
+
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, string(b)
+	}
+
+	optional := handler.GraphQL{Schema: graphql.MustParseSchema(authSchema, &authRoot{}), Authenticator: keys}
+
+	resp, body := post(optional, "secret-key")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.JSONEq(t, `{"data": {"viewer": "mobile-app"}}`, body)
+
+	resp, body = post(optional, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.JSONEq(t, `{"data": {"viewer": null}}`, body)
+
+	resp, _ = post(optional, "wrong-key")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("WWW-Authenticate"), "invalid_token")
+
+	required := optional
+	required.RequireAuthentication = true
+
+	resp, _ = post(required, "")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, body = post(required, "secret-key")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.JSONEq(t, `{"data": {"viewer": "mobile-app"}}`, body)
+}
+
+const authorizationSchema = `
+directive @auth(requires: Role!) on FIELD_DEFINITION
+enum Role { INTERNAL }
+
+schema { query: Query }
+
+type Query {
+	ship: Ship
+	audit: String @auth(requires: INTERNAL)
+}
+
+type Ship {
+	name: String!
+	cost: Int @auth(requires: INTERNAL)
+}
+`
+
+type authorizationRoot struct{ executed bool }
+
+func (r *authorizationRoot) Ship() *authorizationShip {
+	r.executed = true
+	return &authorizationShip{}
+}
+
+func (r *authorizationRoot) Audit() *string { return nil }
+
+type authorizationShip struct{}
+
+func (authorizationShip) Name() string { return "X-wing" }
+
+func (authorizationShip) Cost(ctx context.Context) (*int32, error) {
+	if err := auth.Authorize(ctx, "Ship.cost"); err != nil {
+		return nil, err
+	}
+
+	cost := int32(149999)
+
+	return &cost, nil
+}
+
+func TestAuthorization(t *testing.T) {
+	keys, err := auth.ParseAPIKeys(strings.NewReader("partner-key partner partner\nstaff-key staff internal"))
+	require.NoError(t, err)
+
+	rules, err := auth.NewRules(authorizationSchema)
+	require.NoError(t, err)
+
+	root := &authorizationRoot{}
+
+	ts := httptest.NewServer(handler.GraphQL{
+		Schema:              graphql.MustParseSchema(authorizationSchema, root),
+		Authenticator:       keys,
+		Authorization:       rules,
+		HideForbiddenFields: true,
+	})
+	t.Cleanup(ts.Close)
+
+	post := func(key, query string) string {
+		body, err := json.Marshal(map[string]string{"query": query})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set(auth.APIKeyHeader, key)
+
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(b)
+	}
+
+	// Restricted fields resolve to null with a FORBIDDEN error.
+	body := post("partner-key", `{ ship { name cost } }`)
+	require.JSONEq(t, `{"name": "X-wing", "cost": null}`, jsonAt(t, body, "data", "ship"))
+	require.Equal(t, `"FORBIDDEN"`, jsonAt(t, body, "errors", 0, "extensions", "code"))
+	require.Equal(t, `["ship","cost"]`, jsonAt(t, body, "errors", 0, "path"))
+
+	body = post("staff-key", `{ ship { cost } }`)
+	require.JSONEq(t, `{"data": {"ship": {"cost": 149999}}}`, body)
+
+	// Restricted root fields fail the operation before anything is executed.
+	root.executed = false
+	body = post("partner-key", `{ ship { name } audit }`)
+	require.False(t, root.executed)
+	require.Equal(t, "null", jsonAt(t, body, "data"))
+	require.Equal(t, `"FORBIDDEN"`, jsonAt(t, body, "errors", 0, "extensions", "code"))
+
+	// Introspection only lists the fields the principal may access.
+	introspect := `{ __type(name: "Ship") { fields { name } } }`
+	require.JSONEq(t, `{"data": {"__type": {"fields": [{"name": "name"}]}}}`, post("partner-key", introspect))
+	require.JSONEq(t, `{"data": {"__type": {"fields": [{"name": "name"}, {"name": "cost"}]}}}`, post("staff-key", introspect))
+}
+
+// jsonAt returns the JSON at the path of keys and indexes within the document, or "null".
+func jsonAt(t *testing.T, doc string, path ...interface{}) string {
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc), &v))
+
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			m, _ := v.(map[string]interface{})
+			v = m[p]
+		case int:
+			l, _ := v.([]interface{})
+			if p >= len(l) {
+				return "null"
+			}
+
+			v = l[p]
+		}
+	}
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return string(b)
+}
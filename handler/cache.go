@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tonyghita/graphql-go-example/cache"
+	"github.com/tonyghita/graphql-go-example/language"
+)
+
+// etag returns a strong entity tag for the response body.
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified reports whether the If-None-Match request header matches the entity tag.
+// Weak comparison is used, as required for If-None-Match.
+func notModified(r *http.Request, tag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == "*" || t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cacheKey identifies a request by its normalized queries, operation names and variables, so
+// requests which differ only in formatting share a key.
+// It returns false when a query cannot be normalized.
+func cacheKey(req request) (string, bool) {
+	h := sha256.New()
+
+	if req.isBatch {
+		h.Write([]byte("batch\x00"))
+	}
+
+	for _, q := range req.queries {
+		doc, err := language.Normalize(q.Query)
+		if err != nil {
+			return "", false
+		}
+
+		// Maps are marshalled with sorted keys, so equal variables always encode the same way.
+		vars, err := json.Marshal(q.Variables)
+		if err != nil {
+			return "", false
+		}
+
+		for _, part := range [][]byte{[]byte(doc), []byte(q.OpName), vars} {
+			h.Write(part)
+			h.Write([]byte{0})
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// respondCached writes a response body along with its caching headers, or an empty 304 Not
+// Modified response when the client already holds the body.
+func respondCached(w http.ResponseWriter, r *http.Request, resp cache.Response, now time.Time) {
+	w.Header().Set("Cache-Control", resp.Policy.Header())
+
+	if age := resp.Age(now); age > 0 {
+		w.Header().Set("Age", strconv.Itoa(age))
+	}
+
+	if r.Method != http.MethodGet {
+		respond(w, resp.Body, http.StatusOK)
+		return
+	}
+
+	w.Header().Set("ETag", resp.ETag)
+
+	if notModified(r, resp.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	respond(w, resp.Body, http.StatusOK)
+}
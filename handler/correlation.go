@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// correlationHeader carries the correlation ID of a request.
+// A client or proxy may set it on the request; the server always sets it on the response.
+const correlationHeader = "X-Request-ID"
+
+// maxCorrelationIDLength bounds the length of a client-provided correlation ID.
+const maxCorrelationIDLength = 128
+
+// correlationID returns the correlation ID provided by the client, or a new random ID when the
+// client did not provide a usable one.
+func correlationID(r *http.Request) string {
+	if id := r.Header.Get(correlationHeader); validCorrelationID(id) {
+		return id
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// validCorrelationID reports whether the ID is safe to echo in headers, logs and responses.
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
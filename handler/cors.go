@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The CORS handler lets browsers send cross-origin requests to the wrapped Handler, following the
+// Fetch standard's CORS protocol. It answers preflight requests itself. Requests from origins
+// which are not allowed are passed on without CORS headers, so browsers do not expose the response.
+type CORS struct {
+	Handler http.Handler
+
+	// AllowedOrigins lists the origins, such as "https://app.example.com", which may send requests.
+	// An origin may start with a wildcard subdomain, such as "https://*.example.com", which matches
+	// any subdomain of example.com but not example.com itself. "*" allows any origin, but never
+	// with credentials.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods cross-origin requests may use. GET, HEAD and POST are always
+	// allowed, since the CORS protocol never restricts them.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers cross-origin requests may send. "*" allows any.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers browsers expose to scripts, beyond the few they
+	// always do.
+	ExposedHeaders []string
+	// AllowCredentials lets browsers send cookies and Authorization headers, and expose the
+	// responses to requests which sent them.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the answer to a preflight request. When zero,
+	// browsers use their default of a few seconds.
+	MaxAge time.Duration
+}
+
+func (h CORS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	origin := r.Header.Get("Origin")
+
+	// Responses depend on the origin, so caches must key them by it.
+	header.Add("Vary", "Origin")
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		h.preflight(w, r, origin)
+		return
+	}
+
+	if origin != "" {
+		if wildcard, ok := h.allowsOrigin(origin); ok {
+			h.allowOrigin(header, origin, wildcard)
+
+			if len(h.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(h.ExposedHeaders, ", "))
+			}
+		}
+	}
+
+	h.Handler.ServeHTTP(w, r)
+}
+
+// preflight answers a preflight request, which asks whether the actual request may be sent.
+// The request is refused by answering without CORS headers.
+func (h CORS) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	header := w.Header()
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+
+	method := r.Header.Get("Access-Control-Request-Method")
+	requested := requestedHeaders(r)
+
+	wildcard, ok := h.allowsOrigin(origin)
+	if ok && h.allowsMethod(method) && h.allowsHeaders(requested) {
+		h.allowOrigin(header, origin, wildcard)
+		header.Set("Access-Control-Allow-Methods", method)
+
+		if len(requested) > 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+		}
+
+		if h.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(h.MaxAge.Seconds())))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowOrigin sets the headers which let the origin read the response. An origin only allowed by
+// "*" is never allowed credentials.
+func (h CORS) allowOrigin(header http.Header, origin string, wildcard bool) {
+	if wildcard {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	header.Set("Access-Control-Allow-Origin", origin)
+
+	if h.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// allowsOrigin reports whether the origin may send requests, and whether it is only allowed
+// because any origin is. Origins listed explicitly take precedence over "*", so they can still be
+// allowed credentials.
+func (h CORS) allowsOrigin(origin string) (wildcard bool, ok bool) {
+	origin = strings.ToLower(origin)
+
+	for _, allowed := range h.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+
+		switch {
+		case allowed == "*":
+			wildcard = true
+		case allowed == origin:
+			return false, true
+		case matchesSubdomain(allowed, origin):
+			return false, true
+		}
+	}
+
+	return wildcard, wildcard
+}
+
+// matchesSubdomain reports whether the origin is a subdomain of a pattern such as
+// "https://*.example.com".
+func matchesSubdomain(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+
+	rest := strings.TrimPrefix(origin, scheme+"://")
+	if rest == origin {
+		return false
+	}
+
+	sub := strings.TrimSuffix(rest, "."+host)
+
+	return sub != rest && sub != "" && !strings.ContainsAny(sub, "/:@")
+}
+
+func (h CORS) allowsMethod(method string) bool {
+	if method == http.MethodGet || method == http.MethodPost || method == http.MethodHead {
+		return true
+	}
+
+	for _, m := range h.AllowedMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h CORS) allowsHeaders(requested []string) bool {
+	for _, name := range requested {
+		if !h.allowsHeader(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (h CORS) allowsHeader(name string) bool {
+	for _, allowed := range h.AllowedHeaders {
+		if allowed == "*" || strings.EqualFold(allowed, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestedHeaders returns the lower-cased names of the headers a preflight request asks to send.
+func requestedHeaders(r *http.Request) []string {
+	var names []string
+
+	for _, v := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
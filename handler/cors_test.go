@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/handler"
+)
+
+func TestCORS(t *testing.T) {
+	h := handler.CORS{
+		Handler:          handler.GraphQL{Schema: graphql.MustParseSchema(authSchema, &authRoot{})},
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	serve := func(h http.Handler, method, origin string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/graphql", strings.NewReader(`{"query": "{ viewer }"}`))
+		for name, values := range header {
+			r.Header[name] = values
+		}
+
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w
+	}
+
+	preflight := func(origin, method, headers string) *httptest.ResponseRecorder {
+		return serve(h, http.MethodOptions, origin, http.Header{
+			"Access-Control-Request-Method":  {method},
+			"Access-Control-Request-Headers": {headers},
+		})
+	}
+
+	t.Run("Preflight", func(t *testing.T) {
+		w := preflight("https://app.example.com", "POST", "Content-Type, authorization")
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		require.Equal(t, "POST", w.Header().Get("Access-Control-Allow-Methods"))
+		require.Equal(t, "content-type, authorization", w.Header().Get("Access-Control-Allow-Headers"))
+		require.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+		require.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("PreflightRefused", func(t *testing.T) {
+		for name, w := range map[string]*httptest.ResponseRecorder{
+			"origin": preflight("https://evil.example.com", "POST", "Content-Type"),
+			"method": preflight("https://app.example.com", "DELETE", ""),
+			"header": preflight("https://app.example.com", "POST", "X-Secret"),
+		} {
+			require.Equal(t, http.StatusNoContent, w.Code, name)
+			require.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), name)
+		}
+	})
+
+	t.Run("WildcardSubdomain", func(t *testing.T) {
+		for origin, allowed := range map[string]bool{
+			"https://app.example.org":       true,
+			"https://a.b.example.org":       true,
+			"https://example.org":           false,
+			"http://app.example.org":        false,
+			"https://app.example.org.evil":  false,
+			"https://evil.com/.example.org": false,
+		} {
+			w := serve(h, http.MethodPost, origin, nil)
+			require.Equal(t, http.StatusOK, w.Code, origin)
+
+			if allowed {
+				require.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"), origin)
+				require.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"), origin)
+			} else {
+				require.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+			}
+		}
+	})
+
+	t.Run("AnyOrigin", func(t *testing.T) {
+		anyOrigin := h
+		anyOrigin.AllowedOrigins = []string{"https://app.example.com", "*"}
+
+		w := serve(anyOrigin, http.MethodPost, "https://elsewhere.example.net", nil)
+		require.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		require.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+		w = serve(anyOrigin, http.MethodPost, "https://app.example.com", nil)
+		require.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		w := serve(h, http.MethodOptions, "", nil)
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Equal(t, "GET, POST, OPTIONS", w.Header().Get("Allow"))
+
+		w = serve(h, http.MethodDelete, "", nil)
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		require.Equal(t, "GET, POST, OPTIONS", w.Header().Get("Allow"))
+	})
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+)
+
+// debugHeader is the request header clients ask for tracing and debug extensions with.
+const debugHeader = "X-Debug"
+
+// debug reports whether the request asked for tracing and debug extensions, and may have them.
+// Requests which may not are executed as usual.
+func (h GraphQL) debug(r *http.Request, p *auth.Principal) bool {
+	if !h.Debug {
+		return false
+	}
+
+	if on, err := strconv.ParseBool(r.Header.Get(debugHeader)); err != nil || !on {
+		return false
+	}
+
+	return h.DebugRole == "" || p.HasRole(h.DebugRole)
+}
@@ -0,0 +1,61 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/handler"
+	"github.com/tonyghita/graphql-go-example/tracing"
+)
+
+func TestDebug(t *testing.T) {
+	types, err := tracing.NewTypes(authSchema)
+	require.NoError(t, err)
+
+	keys, err := auth.ParseAPIKeys(strings.NewReader("staff-key ops internal\npartner-key shop partner"))
+	require.NoError(t, err)
+
+	tracer := tracing.Tracer{Tracer: trace.OpenTracingTracer{}, Types: types}
+
+	h := handler.GraphQL{
+		Schema:        graphql.MustParseSchema(authSchema, &authRoot{}, graphql.Tracer(tracer)),
+		Authenticator: keys,
+		Debug:         true,
+		DebugRole:     "internal",
+	}
+
+	post := func(key, debug string) string {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ viewer }"}`))
+		r.Header.Set(auth.APIKeyHeader, key)
+
+		if debug != "" {
+			r.Header.Set("X-Debug", debug)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		if debug != "" {
+			require.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		}
+
+		return w.Body.String()
+	}
+
+	body := post("staff-key", "true")
+	require.Equal(t, "1", jsonAt(t, body, "extensions", "tracing", "version"))
+	require.Equal(t, `["viewer"]`, jsonAt(t, body, "extensions", "tracing", "execution", "resolvers", 0, "path"))
+	require.Equal(t, `{"calls":[],"loaders":[]}`, jsonAt(t, body, "extensions", "debug"))
+
+	// Without the header, or without the role, the response is as usual.
+	require.JSONEq(t, `{"data": {"viewer": "ops"}}`, post("staff-key", ""))
+	require.JSONEq(t, `{"data": {"viewer": "shop"}}`, post("partner-key", "true"))
+}
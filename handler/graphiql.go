@@ -1,56 +1,181 @@
 package handler
 
-import "net/http"
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
 
-// GraphiQL is an in-browser IDE for exploring GraphiQL APIs.
-// This handler returns GraphiQL when requested.
+// assets holds the IDE's page, script, stylesheet and example operations.
 //
-// For more information, see https://github.com/graphql/graphiql.
-type GraphiQL struct{}
+//go:embed graphiql
+var assets embed.FS
+
+var page = template.Must(template.ParseFS(assets, "graphiql/index.html"))
+
+// GraphiQL is an in-browser IDE for exploring GraphQL APIs.
+// This handler returns the IDE when requested.
+//
+// The IDE is a small, dependency-free take on GraphiQL (https://github.com/graphql/graphiql). Its
+// assets are embedded in the binary, so it works without network access, and it is served with a
+// strict Content-Security-Policy.
+type GraphiQL struct {
+	// Endpoint is the URL of the GraphQL API. It defaults to "/graphql".
+	Endpoint string
+	// BasePath is the path the IDE is served under, such as "/graphiql/". It defaults to "/".
+	BasePath string
+	// Examples are the operations offered in the IDE's list of examples. When nil, the examples
+	// embedded in this package are offered.
+	Examples []Example
+}
+
+// An Example is an operation offered in the IDE.
+type Example struct {
+	Name      string                 `json:"name"`
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
 
 func (h GraphiQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
+	if r.Method != "GET" && r.Method != "HEAD" {
 		respond(w, errorJSON("only GET requests are supported"), http.StatusMethodNotAllowed)
 		return
 	}
 
-	w.Write(graphiql)
-}
-
-var graphiql = []byte(`
-<!DOCTYPE html>
-<html>
-	<head>
-		<link rel="stylesheet" href="https://cdnjs.cloudflare.com/ajax/libs/graphiql/0.11.11/graphiql.css"/>
-		<script src="https://cdnjs.cloudflare.com/ajax/libs/fetch/2.0.3/fetch.min.js"></script>
-		<script src="https://cdnjs.cloudflare.com/ajax/libs/react/16.2.0/umd/react.production.min.js"></script>
-		<script src="https://cdnjs.cloudflare.com/ajax/libs/react-dom/16.2.0/umd/react-dom.production.min.js"></script>
-		<script src="https://cdnjs.cloudflare.com/ajax/libs/graphiql/0.11.11/graphiql.min.js"></script>
-	</head>
-	<body style="width: 100%; height: 100%; margin: 0; overflow: hidden;">
-		<div id="graphiql" style="height: 100vh;">Loading...</div>
-		<script>
-			function fetchGQL(params) {
-				return fetch("/graphql", {
-					method: "post",
-					body: JSON.stringify(params),
-					credentials: "include",
-				}).then(function (resp) {
-					return resp.text();
-				}).then(function (body) {
-					try {
-						return JSON.parse(body);
-					} catch (error) {
-						return body;
-					}
-				});
-			}
-
-			ReactDOM.render(
-				React.createElement(GraphiQL, {fetcher: fetchGQL}),
-				document.getElementById("graphiql")
-			)
-		</script>
-	</body>
-</html>
-`)
+	base := h.basePath()
+
+	switch {
+	case r.URL.Path+"/" == base: // Serve the page's relative URLs from under the base path.
+		http.Redirect(w, r, base, http.StatusMovedPermanently)
+		return
+	case !strings.HasPrefix(r.URL.Path, base):
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Security-Policy", h.contentSecurityPolicy())
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	switch name := strings.TrimPrefix(r.URL.Path, base); name {
+	case "", "index.html":
+		var buf bytes.Buffer
+		if err := page.Execute(&buf, struct{ Endpoint, BasePath string }{h.endpoint(), base}); err != nil {
+			http.Error(w, "unable to render page", http.StatusInternalServerError)
+			return
+		}
+
+		serve(w, r, "index.html", buf.Bytes())
+	case "graphiql.js", "graphiql.css":
+		b, err := assets.ReadFile("graphiql/" + name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		serve(w, r, name, b)
+	case "examples.json":
+		examples := h.Examples
+		if examples == nil {
+			examples = embeddedExamples()
+		}
+
+		b, err := json.Marshal(examples)
+		if err != nil {
+			http.Error(w, "unable to encode examples", http.StatusInternalServerError)
+			return
+		}
+
+		serve(w, r, name, b)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h GraphiQL) endpoint() string {
+	if h.Endpoint == "" {
+		return "/graphql"
+	}
+
+	return h.Endpoint
+}
+
+// basePath returns the base path with leading and trailing slashes.
+func (h GraphiQL) basePath() string {
+	p := "/" + strings.Trim(h.BasePath, "/") + "/"
+	if p == "//" {
+		return "/"
+	}
+
+	return p
+}
+
+// contentSecurityPolicy only allows the IDE's own assets, and requests to the GraphQL endpoint.
+func (h GraphiQL) contentSecurityPolicy() string {
+	connect := "'self'"
+
+	if u, err := url.Parse(h.endpoint()); err == nil && u.Scheme != "" && u.Host != "" {
+		connect += " " + u.Scheme + "://" + u.Host
+	}
+
+	return strings.Join([]string{
+		"default-src 'none'",
+		"script-src 'self'",
+		"style-src 'self'",
+		"img-src 'self' data:",
+		"connect-src " + connect,
+		"base-uri 'none'",
+		"form-action 'none'",
+		"frame-ancestors 'none'",
+	}, "; ")
+}
+
+// serve writes the content, answering conditional and range requests. The content type is derived
+// from the name's extension.
+func serve(w http.ResponseWriter, r *http.Request, name string, b []byte) {
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(b))
+}
+
+// embeddedExamples reads the example operations embedded in this package.
+// The first line of each file is a comment naming the example. It may be followed by a comment
+// holding the operation's variables as a JSON object, such as:
+//
+//	# Variables: {"name": "Luke"}
+func embeddedExamples() []Example {
+	files, _ := fs.Glob(assets, "graphiql/examples/*.graphql")
+	examples := make([]Example, 0, len(files))
+
+	for _, f := range files {
+		b, err := assets.ReadFile(f)
+		if err != nil {
+			continue
+		}
+
+		ex := Example{Name: strings.TrimSuffix(path.Base(f), ".graphql")}
+		lines := strings.SplitN(string(b), "\n", 3)
+
+		if len(lines) > 0 && strings.HasPrefix(lines[0], "#") {
+			ex.Name = strings.TrimSpace(strings.TrimPrefix(lines[0], "#"))
+			lines = lines[1:]
+		}
+
+		if len(lines) > 0 && strings.HasPrefix(lines[0], "# Variables:") {
+			_ = json.Unmarshal([]byte(strings.TrimPrefix(lines[0], "# Variables:")), &ex.Variables)
+			lines = lines[1:]
+		}
+
+		ex.Query = strings.Join(lines, "\n")
+		examples = append(examples, ex)
+	}
+
+	return examples
+}
@@ -0,0 +1,247 @@
+// A small GraphQL IDE. It has no dependencies, so it works without network access, and it runs
+// under a Content-Security-Policy which forbids inline scripts and styles.
+(function () {
+	"use strict";
+
+	var root = document.getElementById("graphiql");
+	var endpoint = root.dataset.endpoint;
+
+	var el = {
+		run: document.getElementById("run"),
+		examples: document.getElementById("examples"),
+		history: document.getElementById("history"),
+		clearHistory: document.getElementById("clear-history"),
+		status: document.getElementById("status"),
+		query: document.getElementById("query"),
+		variables: document.getElementById("variables"),
+		headers: document.getElementById("headers"),
+		result: document.getElementById("result"),
+	};
+
+	// Queries, variables and history persist across visits. Headers often hold credentials, so they
+	// only persist for the lifetime of the tab.
+	var store = {
+		get: function (storage, key, fallback) {
+			try {
+				var v = storage.getItem("graphiql:" + key);
+				return v === null ? fallback : JSON.parse(v);
+			} catch (e) {
+				return fallback;
+			}
+		},
+		set: function (storage, key, value) {
+			try {
+				storage.setItem("graphiql:" + key, JSON.stringify(value));
+			} catch (e) {
+				// Storage may be full or disabled; nothing is lost but persistence.
+			}
+		},
+	};
+
+	var maxHistory = 25;
+	var history = store.get(localStorage, "history", []);
+	var examples = [];
+
+	el.query.value = store.get(localStorage, "query", "# Press Ctrl-Enter to run the query.\n{\n  films {\n    title\n  }\n}\n");
+	el.variables.value = store.get(localStorage, "variables", "");
+	el.headers.value = store.get(sessionStorage, "headers", "");
+
+	el.query.addEventListener("input", function () { store.set(localStorage, "query", el.query.value); });
+	el.variables.addEventListener("input", function () { store.set(localStorage, "variables", el.variables.value); });
+	el.headers.addEventListener("input", function () { store.set(sessionStorage, "headers", el.headers.value); });
+
+	function setStatus(text, isError) {
+		el.status.textContent = text;
+		el.status.classList.toggle("error", !!isError);
+	}
+
+	// parseObject parses the JSON object in an editor, which may be empty.
+	function parseObject(textarea, name) {
+		var text = textarea.value.trim();
+		if (text === "") {
+			return {};
+		}
+
+		var v = JSON.parse(text);
+		if (v === null || typeof v !== "object" || Array.isArray(v)) {
+			throw new Error(name + " must be a JSON object");
+		}
+
+		return v;
+	}
+
+	// operationAtCursor returns the name of the operation the cursor is in, so documents with several
+	// operations run the one being edited.
+	function operationAtCursor() {
+		var re = /(?:^|\n)\s*(query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)/g;
+		var cursor = el.query.selectionStart;
+		var name = null;
+		var count = 0;
+		var m;
+
+		while ((m = re.exec(el.query.value)) !== null) {
+			count++;
+			if (m.index <= cursor || name === null) {
+				name = m[2];
+			}
+		}
+
+		return count > 1 ? name : null;
+	}
+
+	function run() {
+		var variables, headers;
+
+		try {
+			variables = parseObject(el.variables, "Variables");
+			headers = parseObject(el.headers, "Headers");
+		} catch (e) {
+			setStatus(e.message, true);
+			return;
+		}
+
+		var body = { query: el.query.value, variables: variables, operationName: operationAtCursor() };
+		var init = {
+			method: "POST",
+			credentials: "same-origin",
+			headers: Object.assign({ "Content-Type": "application/json", "Accept": "application/json" }, headers),
+			body: JSON.stringify(body),
+		};
+
+		remember(body);
+		setStatus("Running…");
+		el.run.disabled = true;
+
+		var started = performance.now();
+
+		fetch(endpoint, init).then(function (resp) {
+			return resp.text().then(function (text) {
+				var elapsed = Math.round(performance.now() - started);
+				setStatus(resp.status + " " + resp.statusText + " · " + elapsed + " ms", !resp.ok);
+
+				try {
+					el.result.textContent = JSON.stringify(JSON.parse(text), null, 2);
+				} catch (e) {
+					el.result.textContent = text;
+				}
+			});
+		}).catch(function (e) {
+			setStatus(e.message, true);
+		}).then(function () {
+			el.run.disabled = false;
+		});
+	}
+
+	function remember(entry) {
+		var variables = el.variables.value.trim();
+
+		history = history.filter(function (h) {
+			return h.query !== entry.query || h.variables !== variables;
+		});
+		history.unshift({ query: entry.query, variables: variables, operationName: entry.operationName, at: Date.now() });
+		history = history.slice(0, maxHistory);
+
+		store.set(localStorage, "history", history);
+		renderHistory();
+	}
+
+	function option(value, label) {
+		var o = document.createElement("option");
+		o.value = value;
+		o.textContent = label;
+		return o;
+	}
+
+	function resetSelect(select) {
+		while (select.options.length > 1) {
+			select.remove(1);
+		}
+	}
+
+	function renderHistory() {
+		resetSelect(el.history);
+
+		history.forEach(function (h, i) {
+			var label = h.operationName || h.query.replace(/#[^\n]*/g, "").replace(/\s+/g, " ").trim();
+			if (label.length > 60) {
+				label = label.slice(0, 57) + "…";
+			}
+
+			el.history.appendChild(option(String(i), new Date(h.at).toLocaleTimeString() + " – " + label));
+		});
+	}
+
+	function load(query, variables) {
+		el.query.value = query;
+		el.variables.value = variables || "";
+		store.set(localStorage, "query", el.query.value);
+		store.set(localStorage, "variables", el.variables.value);
+		el.query.focus();
+	}
+
+	el.history.addEventListener("change", function () {
+		var h = history[Number(el.history.value)];
+		if (h) {
+			load(h.query, h.variables);
+		}
+		el.history.value = "";
+	});
+
+	el.clearHistory.addEventListener("click", function () {
+		history = [];
+		store.set(localStorage, "history", history);
+		renderHistory();
+	});
+
+	el.examples.addEventListener("change", function () {
+		var ex = examples[Number(el.examples.value)];
+		if (ex) {
+			load(ex.query, ex.variables ? JSON.stringify(ex.variables, null, 2) : "");
+		}
+		el.examples.value = "";
+	});
+
+	fetch(root.dataset.examples, { credentials: "same-origin" }).then(function (resp) {
+		return resp.ok ? resp.json() : [];
+	}).then(function (list) {
+		examples = list || [];
+		examples.forEach(function (ex, i) {
+			el.examples.appendChild(option(String(i), ex.name));
+		});
+	}).catch(function () {
+		// The IDE works without examples.
+	});
+
+	document.querySelectorAll(".tab").forEach(function (tab) {
+		tab.addEventListener("click", function () {
+			document.querySelectorAll(".tab").forEach(function (t) {
+				var active = t === tab;
+				t.classList.toggle("active", active);
+				document.getElementById(t.dataset.pane).classList.toggle("hidden", !active);
+			});
+		});
+	});
+
+	// Tab indents instead of moving focus, and Ctrl-Enter (Cmd-Enter on macOS) runs the query.
+	document.querySelectorAll(".editor").forEach(function (editor) {
+		editor.addEventListener("keydown", function (e) {
+			if (e.key === "Tab" && !e.shiftKey && !e.ctrlKey && !e.metaKey && !e.altKey) {
+				e.preventDefault();
+
+				var start = editor.selectionStart;
+				editor.setRangeText("  ", start, editor.selectionEnd, "end");
+				editor.dispatchEvent(new Event("input"));
+			}
+		});
+	});
+
+	document.addEventListener("keydown", function (e) {
+		if (e.key === "Enter" && (e.ctrlKey || e.metaKey)) {
+			e.preventDefault();
+			run();
+		}
+	});
+
+	el.run.addEventListener("click", run);
+	renderHistory();
+})();
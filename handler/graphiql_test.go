@@ -1,13 +1,18 @@
 package handler_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
 	"github.com/tonyghita/graphql-go-example/handler"
+	"github.com/tonyghita/graphql-go-example/schema"
 )
 
 func TestGraphiQLHandler(t *testing.T) {
@@ -59,3 +64,86 @@ func fatalIfErr(t *testing.T, err error) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestGraphiQLAssets(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(handler.GraphiQL{Endpoint: "https://api.example.com/graphql", BasePath: "/ide"})
+	defer ts.Close()
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"/ide/", http.StatusOK, "text/html; charset=utf-8", `data-endpoint="https://api.example.com/graphql"`},
+		{"/ide/index.html", http.StatusOK, "text/html; charset=utf-8", `src="/ide/graphiql.js"`},
+		{"/ide/graphiql.js", http.StatusOK, "text/javascript; charset=utf-8", "fetch(endpoint"},
+		{"/ide/graphiql.css", http.StatusOK, "text/css; charset=utf-8", "#graphiql"},
+		{"/ide/examples.json", http.StatusOK, "application/json", `"name":"All films"`},
+		{"/ide/missing.js", http.StatusNotFound, "", ""},
+		{"/elsewhere", http.StatusNotFound, "", ""},
+	}
+
+	for _, test := range tests {
+		resp, err := ts.Client().Get(ts.URL + test.path)
+		require.NoError(t, err)
+
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		require.Equal(t, test.status, resp.StatusCode, test.path)
+
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		require.Equal(t, test.contentType, resp.Header.Get("Content-Type"), test.path)
+		require.Contains(t, string(b), test.contains, test.path)
+
+		csp := resp.Header.Get("Content-Security-Policy")
+		require.Contains(t, csp, "script-src 'self'", test.path)
+		require.Contains(t, csp, "connect-src 'self' https://api.example.com", test.path)
+		require.NotContains(t, csp, "unsafe-inline", test.path)
+	}
+
+	// The page is not served outside the base path, but the base path without its trailing slash
+	// redirects to it.
+	client := ts.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := client.Get(ts.URL + "/ide")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	require.Equal(t, "/ide/", resp.Header.Get("Location"))
+}
+
+func TestGraphiQLExamplesAreValid(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.String()
+	require.NoError(t, err)
+
+	gqlSchema, err := graphql.ParseSchema(s, nil, graphql.UseStringDescriptions())
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(handler.GraphiQL{})
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/examples.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var examples []handler.Example
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&examples))
+	require.NotEmpty(t, examples)
+
+	for _, ex := range examples {
+		require.NotEmpty(t, ex.Name)
+		require.Empty(t, gqlSchema.ValidateWithVariables(ex.Query, ex.Variables), ex.Name)
+	}
+}
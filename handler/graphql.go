@@ -1,14 +1,24 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"sync"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/cache"
 	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/incremental"
+	"github.com/tonyghita/graphql-go-example/limit"
 	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/ratelimit"
+	"github.com/tonyghita/graphql-go-example/tracing"
+	"github.com/tonyghita/graphql-go-example/usage"
 )
 
 // The GraphQL handler handles GraphQL API requests over HTTP.
@@ -17,15 +27,67 @@ type GraphQL struct {
 	Schema  *graphql.Schema
 	Loaders loader.Collection
 	Logger  logger
+
+	// MaskErrors replaces the messages of errors which are not caused by user input with generic
+	// text. The original messages are logged along with the request's correlation ID.
+	MaskErrors bool
+
+	// MaxParallelOperations bounds how many operations of a batched request execute at once.
+	// When zero, every operation executes at once.
+	MaxParallelOperations int
+
+	// Responses stores cacheable responses to GET requests, so identical requests are answered
+	// without executing them again. When nil, every request is executed.
+	Responses *cache.Responses
+
+	// Usage records the schema coordinates used by each operation, by client. When nil, usage is
+	// not recorded.
+	Usage *usage.Tracker
+
+	// Authenticator authenticates requests. When nil, every request is anonymous.
+	Authenticator auth.Authenticator
+	// RequireAuthentication rejects anonymous requests. Otherwise they are executed without a
+	// principal.
+	RequireAuthentication bool
+
+	// Authorization holds the roles required to access fields, which resolvers check with
+	// auth.Authorize. When nil, every restricted field is forbidden.
+	Authorization *auth.Rules
+	// HideForbiddenFields removes the fields the principal may not access from introspection
+	// results.
+	HideForbiddenFields bool
+
+	// RateLimits limits the requests and the query cost of each client. When nil, clients are not
+	// limited.
+	RateLimits *ratelimit.Limiter
+
+	// Debug adds Apollo tracing, and the loader batches and SWAPI calls of each operation, to the
+	// extensions of responses to requests which ask for them with the X-Debug header. When
+	// DebugRole is set, only principals granted it may ask.
+	Debug     bool
+	DebugRole string
 }
 
 func (h GraphQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Identify the request so errors reported to the client can be found in the server logs.
+	id := correlationID(r)
+	w.Header().Set(correlationHeader, id)
+
 	// Validate the request.
 	if ok := isSupported(r.Method); !ok {
+		w.Header().Set("Allow", allowedMethods)
 		respond(w, errorJSON("only POST or GET requests are supported"), http.StatusMethodNotAllowed)
 		return
 	}
 
+	// OPTIONS requests ask which methods are supported. Preflight requests sent by browsers before
+	// cross-origin requests are answered by the CORS handler, when the handler is wrapped in one.
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Allow", allowedMethods)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	req, err := parse(r)
 	if err != nil {
 		respond(w, errorJSON(err.Error()), http.StatusBadRequest)
@@ -38,37 +100,84 @@ func (h GraphQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// NOTE: User authentication should happen here, if needed.
-	//
-	// Authentication determines who the request originated from.
-	// Authorization business logic (in services, not this API) will rely on this authentication data.
-	//
-	// We don't need it for this example application, but a typical production application would
-	// perform request authentication.
-	//
-	// The result of authentication should probably be placed on the request context so it can be
-	// passed to resolvers and loaders.
+	// Authentication determines who the request originated from. The principal is placed on the
+	// request context, so it can be passed to resolvers and loaders.
+	principal, ok := h.authenticate(w, r, id)
+	if !ok {
+		return
+	}
+
+	// Limit each client before doing any work for it, including answering from the response cache.
+	if ok := h.rateLimit(w, r, id, principal, req.queries); !ok {
+		return
+	}
+
+	// Record which parts of the schema each client uses, including operations answered from the
+	// response cache, so deprecated fields are only removed once no client uses them.
+	h.recordUsage(r, req.queries)
 
 	// Here, begin request execution...
+	ctx := auth.Attach(r.Context(), principal)
+	if h.Authorization != nil {
+		ctx = h.Authorization.Attach(ctx)
+	}
+
+	ctx = h.Loaders.Attach(ctx) // Attach dataloaders onto the request context.
+
+	// Upstream calls made by all operations in this request count towards one per-request limit.
+	ctx = limit.Attach(ctx)
+
+	// Deliver deferred fragments and streamed lists as they resolve, when the client accepts it.
+	if !req.isBatch && acceptsMultipart(r) {
+		q := req.queries[0]
+
+		if plan, err := incremental.Split(q.Query, q.OpName, q.Variables); err == nil && plan != nil {
+			h.serveIncremental(ctx, w, id, q, plan)
+			return
+		}
+	}
+
+	debug := h.debug(r, principal)
+
+	// Answer GET requests from the response cache when possible.
+	var key string
+
+	// Responses to authenticated requests may depend on the principal, so they are never shared.
+	// Traced responses describe a single execution, so they are neither cached nor answered from
+	// the cache.
+	useCache := h.Responses != nil && r.Method == http.MethodGet && principal == nil && !debug
+	if useCache {
+		key, useCache = cacheKey(req)
+	}
+
+	if useCache {
+		now := time.Now()
+
+		if cached, ok := h.Responses.Get(key, now); ok {
+			respondCached(w, r, cached, now)
+			return
+		}
+	}
+
 	var (
-		ctx       = h.Loaders.Attach(r.Context()) // Attach dataloaders onto the request context.
-		responses = make([]*graphql.Response, n)  // Allocate a slice large enough for all responses.
-		wg        sync.WaitGroup                  // Use the WaitGroup to wait for all executions to finish.
+		responses = make([]*graphql.Response, n) // Allocate a slice large enough for all responses.
+		policies  = make([]cache.Policy, n)      // The cache policy of each operation.
+		wg        sync.WaitGroup                 // Use the WaitGroup to wait for all executions to finish.
+		sem       = make(chan struct{}, h.parallelism(n))
 	)
 
 	wg.Add(n)
 
 	for i, q := range req.queries {
 		// Loop through the parsed queries from the request.
-		// These queries are executed in separate goroutines so they process in parallel.
-		go func(i int, q query) {
-			res := h.Schema.Exec(ctx, q.Query, q.OpName, q.Variables)
+		// These queries are executed in separate goroutines so they process in parallel, up to the
+		// configured maximum.
+		sem <- struct{}{}
 
-			// We have to do some work here to expand errors when it is possible for a resolver to return
-			// more than one error (for example, a list resolver).
-			res.Errors = errors.Expand(res.Errors)
+		go func(i int, q query) {
+			defer func() { <-sem }()
 
-			responses[i] = res
+			responses[i], policies[i] = h.execute(ctx, id, q, debug)
 			wg.Done()
 		}(i, q)
 	}
@@ -95,7 +204,77 @@ func (h GraphQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respond(w, resp, http.StatusOK)
+	// The response may only be cached as long as its most restrictive operation allows.
+	policy := policies[0]
+	for _, p := range policies[1:] {
+		policy = policy.Restrict(p)
+	}
+
+	// Shared caches must not answer requests which need authentication.
+	if principal != nil || h.RequireAuthentication {
+		policy.Scope = cache.Private
+	}
+
+	stored := cache.Response{Body: resp, ETag: etag(resp), Policy: policy, Stored: time.Now()}
+	if useCache {
+		h.Responses.Put(key, stored)
+	}
+
+	respondCached(w, r, stored, stored.Stored)
+}
+
+// execute executes a single operation, and prepares its errors and extensions for the client.
+// It also returns the operation's cache policy, which forbids caching when the operation failed or
+// was traced.
+func (h GraphQL) execute(ctx context.Context, id string, q query, debug bool) (*graphql.Response, cache.Policy) {
+	// Each operation collects its own data-quality warnings, and the errors of list items.
+	ctx, report := normalize.Attach(ctx)
+	ctx, collector := errors.Collect(ctx)
+	// Each operation records the cache hints of the fields it resolves.
+	ctx, recorder := cache.Attach(ctx)
+
+	var tr *tracing.Trace
+	if debug {
+		ctx, tr = tracing.Attach(ctx)
+	}
+
+	res := h.authorizeAndExec(ctx, q)
+	res.Errors = append(res.Errors, collector.Errors()...)
+
+	// We have to do some work here to expand errors when it is possible for a resolver to return
+	// more than one error (for example, a list resolver).
+	res.Errors = errors.Expand(res.Errors)
+	errors.Annotate(res.Errors, id)
+
+	if h.MaskErrors {
+		for _, err := range errors.Mask(res.Errors) {
+			h.logf("[%s] %s: %s %v", id, err.Extensions["code"], err.Message, err.Path)
+		}
+	}
+
+	// Tell the client about any values that SWAPI could not provide exactly.
+	if warnings := report.Warnings(); len(warnings) > 0 {
+		res.Extensions = map[string]interface{}{"warnings": warnings}
+	}
+
+	if tr != nil {
+		tr.Finish()
+
+		if res.Extensions == nil {
+			res.Extensions = map[string]interface{}{}
+		}
+
+		for k, v := range tr.Extensions() {
+			res.Extensions[k] = v
+		}
+	}
+
+	// Responses with errors, and traced responses, are never cached.
+	if len(res.Errors) > 0 || tr != nil {
+		return res, cache.Policy{}
+	}
+
+	return res, recorder.Policy()
 }
 
 // logger defines an interface with a single method.
@@ -103,6 +282,21 @@ type logger interface {
 	Printf(fmt string, values ...interface{})
 }
 
+// parallelism returns the number of operations of a batch of n which may execute at once.
+func (h GraphQL) parallelism(n int) int {
+	if h.MaxParallelOperations > 0 && h.MaxParallelOperations < n {
+		return h.MaxParallelOperations
+	}
+
+	return n
+}
+
+func (h GraphQL) logf(format string, values ...interface{}) {
+	if h.Logger != nil {
+		h.Logger.Printf(format, values...)
+	}
+}
+
 // A request respresents an HTTP request to the GraphQL endpoint.
 // A request can have a single query or a batch of requests with one or more queries.
 // It is important to distinguish between a single query request and a batch request with a single query.
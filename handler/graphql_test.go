@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/cache"
+	"github.com/tonyghita/graphql-go-example/handler"
+)
+
+const cacheSchema = `
+directive @cacheControl(maxAge: Int, scope: CacheControlScope) on FIELD_DEFINITION | OBJECT
+enum CacheControlScope { PUBLIC PRIVATE }
+
+schema { query: Query }
+
+type Query {
+	greeting: String @cacheControl(maxAge: 60)
+	failing: String @cacheControl(maxAge: 60)
+}
+`
+
+type cacheRoot struct{ calls int32 }
+
+func (r *cacheRoot) Greeting() *string {
+	atomic.AddInt32(&r.calls, 1)
+
+	s := "hello"
+	return &s
+}
+
+func (r *cacheRoot) Failing() (*string, error) {
+	return nil, errors.New("failed")
+}
+
+func newCacheHandler(t *testing.T) (*httptest.Server, *cacheRoot) {
+	hints, err := cache.NewHints(cacheSchema)
+	require.NoError(t, err)
+
+	root := &cacheRoot{}
+	tracer := cache.Tracer{Tracer: trace.OpenTracingTracer{}, Hints: hints}
+
+	ts := httptest.NewServer(handler.GraphQL{
+		Schema:    graphql.MustParseSchema(cacheSchema, root, graphql.Tracer(tracer)),
+		Responses: cache.NewResponses(8),
+	})
+	t.Cleanup(ts.Close)
+
+	return ts, root
+}
+
+func get(t *testing.T, ts *httptest.Server, query string, header http.Header) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"?query="+url.QueryEscape(query), nil)
+	require.NoError(t, err)
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	return resp
+}
+
+func TestCacheHeaders(t *testing.T) {
+	ts, _ := newCacheHandler(t)
+
+	resp := get(t, ts, `{ greeting }`, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "max-age=60, public", resp.Header.Get("Cache-Control"))
+
+	tag := resp.Header.Get("ETag")
+	require.NotEmpty(t, tag)
+
+	resp = get(t, ts, `{ greeting }`, http.Header{"If-None-Match": {`"other", ` + tag}})
+	require.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	// Responses with errors are never cached.
+	resp = get(t, ts, `{ greeting failing }`, nil)
+	require.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+	// POST responses carry a policy, but no entity tag.
+	post, err := ts.Client().Post(ts.URL, "application/json", strings.NewReader(`{"query": "{ greeting }"}`))
+	require.NoError(t, err)
+	_ = post.Body.Close()
+
+	require.Equal(t, "max-age=60, public", post.Header.Get("Cache-Control"))
+	require.Empty(t, post.Header.Get("ETag"))
+}
+
+func TestResponseCache(t *testing.T) {
+	ts, root := newCacheHandler(t)
+
+	first := get(t, ts, `{ greeting }`, nil)
+	// Queries which differ only in formatting share a cached response.
+	second := get(t, ts, "{\n  greeting, # comment\n}", nil)
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&root.calls))
+	require.Equal(t, first.Header.Get("ETag"), second.Header.Get("ETag"))
+
+	get(t, ts, `query Named { greeting }`, nil)
+	require.Equal(t, int32(2), atomic.LoadInt32(&root.calls))
+}
@@ -14,8 +14,11 @@ func respond(w http.ResponseWriter, body []byte, code int) {
 	_, _ = w.Write(body)
 }
 
+// allowedMethods lists the methods the GraphQL handler supports, for the Allow header.
+const allowedMethods = "GET, POST, OPTIONS"
+
 func isSupported(method string) bool {
-	return method == "POST" || method == "GET"
+	return method == "POST" || method == "GET" || method == "OPTIONS"
 }
 
 func errorJSON(msg string) []byte {
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tonyghita/graphql-go-example/incremental"
+)
+
+// multipartContentType is the content type of incrementally delivered responses. The deferSpec
+// parameter names the version of the incremental delivery format, as understood by Apollo Client.
+const multipartContentType = `multipart/mixed; boundary="-"; deferSpec=20220824`
+
+// acceptsMultipart reports whether the client accepts incrementally delivered responses.
+func acceptsMultipart(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "multipart/mixed")
+}
+
+// serveIncremental executes the queries of the plan at once, and sends the initial payload followed
+// by a subsequent payload for each deferred fragment or streamed field as soon as it resolves.
+// Incrementally delivered operations are not traced.
+func (h GraphQL) serveIncremental(ctx context.Context, w http.ResponseWriter, id string, q query, plan *incremental.Plan) {
+	results := make(chan *incremental.Payload, len(plan.Parts))
+
+	for _, part := range plan.Parts {
+		go func(part *incremental.Part) {
+			res, _ := h.execute(ctx, id, query{Query: part.Query, OpName: q.OpName, Variables: q.Variables}, false)
+
+			payload, err := part.Payload(res)
+			if err != nil {
+				h.logf("[%s] incremental payload: %s", id, err)
+				payload = &incremental.Payload{}
+			}
+
+			results <- payload
+		}(part)
+	}
+
+	res, _ := h.execute(ctx, id, query{Query: plan.Query, OpName: q.OpName, Variables: q.Variables}, false)
+
+	initial, rest, err := plan.Initial(res)
+	if err != nil {
+		h.logf("[%s] initial payload: %s", id, err)
+		respond(w, errorJSON("server error"), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", multipartContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	mw := newMultipartWriter(w)
+	pending := len(plan.Parts)
+
+	// When the operation could not be executed at all, the parts fail the same way; only report the
+	// errors once.
+	if len(res.Data) == 0 {
+		pending, rest = 0, nil
+	}
+
+	initial.HasNext = pending > 0 || len(rest) > 0
+	if err := mw.write(initial); err != nil {
+		return // The client has gone away.
+	}
+
+	if len(rest) > 0 {
+		if err := mw.write(&incremental.Payload{Incremental: rest, HasNext: pending > 0}); err != nil {
+			return
+		}
+	}
+
+	for ; pending > 0; pending-- {
+		payload := <-results
+		payload.HasNext = pending > 1
+
+		if err := mw.write(payload); err != nil {
+			return
+		}
+	}
+
+	mw.close()
+}
+
+// A multipartWriter writes the parts of a multipart/mixed response with the boundary "-", flushing
+// each part to the client as it is written.
+type multipartWriter struct {
+	w     io.Writer
+	flush func()
+}
+
+func newMultipartWriter(w http.ResponseWriter) *multipartWriter {
+	mw := &multipartWriter{w: w, flush: func() {}}
+	if f, ok := w.(http.Flusher); ok {
+		mw.flush = f.Flush
+	}
+
+	// Every part is preceded by a delimiter; write the first one up front so each part can end with
+	// the delimiter of the next.
+	_, _ = io.WriteString(w, "\r\n---")
+
+	return mw
+}
+
+func (mw *multipartWriter) write(payload *incremental.Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(mw.w, "\r\nContent-Type: application/json; charset=utf-8\r\n\r\n"); err != nil {
+		return err
+	}
+
+	if _, err = mw.w.Write(body); err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(mw.w, "\r\n---"); err != nil {
+		return err
+	}
+
+	mw.flush()
+
+	return nil
+}
+
+// close ends the last delimiter as the close delimiter.
+func (mw *multipartWriter) close() {
+	_, _ = io.WriteString(mw.w, "--\r\n")
+	mw.flush()
+}
@@ -0,0 +1,143 @@
+package handler_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/handler"
+)
+
+const incrementalSchema = `
+directive @defer(label: String, if: Boolean = true) on FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @stream(label: String, if: Boolean = true, initialCount: Int = 0) on FIELD
+
+schema { query: Query }
+
+type Query { film: Film }
+
+type Film {
+	title: String!
+	characters: [Person!]!
+}
+
+type Person { name: String! }
+`
+
+type incrementalRoot struct{ release chan struct{} }
+
+type incrementalFilm struct{ release chan struct{} }
+
+type incrementalPerson struct{ name string }
+
+func (r *incrementalRoot) Film() *incrementalFilm { return &incrementalFilm{release: r.release} }
+
+func (f *incrementalFilm) Title() string { return "A New Hope" }
+
+// Characters blocks until the test releases it, so the initial payload must be sent without it.
+func (f *incrementalFilm) Characters() ([]*incrementalPerson, error) {
+	<-f.release
+	return []*incrementalPerson{{"Luke"}, {"Leia"}}, nil
+}
+
+func (p *incrementalPerson) Name() string { return p.name }
+
+func TestIncrementalDelivery(t *testing.T) {
+	root := &incrementalRoot{release: make(chan struct{})}
+
+	ts := httptest.NewServer(handler.GraphQL{Schema: graphql.MustParseSchema(incrementalSchema, root)})
+	defer ts.Close()
+
+	body := `{"query": "{ film { title ... @defer(label: \"cast\") { characters { name } } } }"}`
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Accept", "multipart/mixed; deferSpec=20220824, application/json")
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+	require.Equal(t, "-", params["boundary"])
+
+	// mime/multipart waits for the bytes following a delimiter before it returns a part, but the
+	// delimiter after each part is only followed by the next part, so read the parts by hand.
+	parts := bufio.NewReader(resp.Body)
+	require.Equal(t, "", readUntilDelimiter(t, parts))
+
+	// The initial payload arrives while the deferred field is still blocked.
+	require.JSONEq(t, `{"data": {"film": {"title": "A New Hope"}}, "hasNext": true}`, nextPart(t, parts))
+
+	close(root.release)
+
+	require.JSONEq(t, `{
+		"incremental": [{"data": {"characters": [{"name": "Luke"}, {"name": "Leia"}]}, "path": ["film"], "label": "cast"}],
+		"hasNext": false
+	}`, nextPart(t, parts))
+
+	rest, err := io.ReadAll(parts)
+	require.NoError(t, err)
+	require.Equal(t, "--\r\n", string(rest))
+}
+
+func TestIncrementalDeliveryNotAccepted(t *testing.T) {
+	root := &incrementalRoot{release: make(chan struct{})}
+	close(root.release)
+
+	ts := httptest.NewServer(handler.GraphQL{Schema: graphql.MustParseSchema(incrementalSchema, root)})
+	defer ts.Close()
+
+	body := `{"query": "{ film { title characters @stream { name } } }"}`
+
+	resp, err := ts.Client().Post(ts.URL, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	var res struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	require.JSONEq(t, `{"film": {"title": "A New Hope", "characters": [{"name": "Luke"}, {"name": "Leia"}]}}`, string(res.Data))
+}
+
+// nextPart reads the next part and the delimiter which follows it, returning the part's body.
+func nextPart(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	part := readUntilDelimiter(t, r)
+
+	header, body, ok := strings.Cut(part, "\r\n\r\n")
+	require.True(t, ok, part)
+	require.Equal(t, "\r\nContent-Type: application/json; charset=utf-8", header)
+
+	return body
+}
+
+func readUntilDelimiter(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	for !bytes.HasSuffix(buf.Bytes(), []byte("\r\n---")) {
+		c, err := r.ReadByte()
+		require.NoError(t, err)
+
+		buf.WriteByte(c)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\r\n---")
+}
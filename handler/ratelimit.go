@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/ratelimit"
+)
+
+// rateLimit spends the request and the estimated cost of its operations from the client's budgets,
+// and sets the RateLimit-* headers (draft-ietf-httpapi-ratelimit-headers) for the budget closest to
+// running out. When the client is over its limit, it responds with 429 Too Many Requests and
+// returns false.
+func (h GraphQL) rateLimit(w http.ResponseWriter, r *http.Request, id string, p *auth.Principal, queries []query) bool {
+	if h.RateLimits == nil {
+		return true
+	}
+
+	cost := 0
+
+	for _, q := range queries {
+		// Operations which cannot be parsed fail validation, and cost nothing.
+		c, _ := h.RateLimits.Costs.Estimate(q.Query, q.OpName, q.Variables)
+		cost += c
+	}
+
+	tier := h.RateLimits.Tier(p)
+
+	// An operation costing more than the whole budget could never execute, so there is no point
+	// asking the client to retry it.
+	if !tier.Cost.Unlimited() && cost > tier.Cost.Capacity {
+		msg := fmt.Sprintf("query cost %d exceeds the limit of %d", cost, tier.Cost.Capacity)
+		respond(w, errorJSON(msg), http.StatusBadRequest)
+		return false
+	}
+
+	res, err := h.RateLimits.Allow(r.Context(), clientKey(r, p), tier, cost, time.Now())
+	if err != nil {
+		// Fail open: an unavailable store must not take the API down with it.
+		h.logf("[%s] rate limiting: %s", id, err)
+		return true
+	}
+
+	setRateLimitHeaders(w.Header(), res)
+
+	if res.Allowed() {
+		return true
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter())))
+	respond(w, errorJSON("rate limit exceeded"), http.StatusTooManyRequests)
+
+	return false
+}
+
+// rateLimitFailure spends a request from the anonymous budget of the IP address a request which
+// failed authentication was sent from. When the budget is spent, it responds with 429 Too Many
+// Requests and returns false.
+func (h GraphQL) rateLimitFailure(w http.ResponseWriter, r *http.Request, id string) bool {
+	if h.RateLimits == nil {
+		return true
+	}
+
+	res, err := h.RateLimits.Allow(r.Context(), clientKey(r, nil), h.RateLimits.Anonymous, 0, time.Now())
+	if err != nil {
+		// Fail open, as for any other request.
+		h.logf("[%s] rate limiting: %s", id, err)
+		return true
+	}
+
+	if res.Allowed() {
+		return true
+	}
+
+	setRateLimitHeaders(w.Header(), res)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter())))
+	respond(w, errorJSON("rate limit exceeded"), http.StatusTooManyRequests)
+
+	return false
+}
+
+// clientKey identifies the client whose budgets a request is spent from: its principal, or the IP
+// address it was sent from when it is anonymous.
+func clientKey(r *http.Request, p *auth.Principal) string {
+	if p != nil {
+		return p.Method + ":" + p.Subject
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+func setRateLimitHeaders(header http.Header, res ratelimit.Result) {
+	var (
+		binding  *ratelimit.Decision
+		policies []string
+	)
+
+	for _, b := range []struct {
+		name string
+		d    ratelimit.Decision
+	}{{"requests", res.Requests}, {"cost", res.Cost}} {
+		d := b.d
+		if d.Limit.Unlimited() {
+			continue
+		}
+
+		policies = append(policies, fmt.Sprintf(`%d;w=%d;comment="%s"`, d.Limit.Capacity, seconds(d.Limit.Per), b.name))
+
+		if binding == nil || binding.Allowed && (!d.Allowed || fraction(d) < fraction(*binding)) {
+			binding = &d
+		}
+	}
+
+	if binding == nil {
+		return
+	}
+
+	header.Set("RateLimit-Limit", strconv.Itoa(binding.Limit.Capacity))
+	header.Set("RateLimit-Remaining", strconv.Itoa(binding.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(binding.Reset)))
+	header.Set("RateLimit-Policy", strings.Join(policies, ", "))
+}
+
+// fraction returns the fraction of the budget which remains.
+func fraction(d ratelimit.Decision) float64 {
+	return float64(d.Remaining) / float64(d.Limit.Capacity)
+}
+
+// seconds rounds the duration up to whole seconds, so clients never retry too early.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/handler"
+	"github.com/tonyghita/graphql-go-example/ratelimit"
+)
+
+const rateLimitSchema = `
+schema { query: Query }
+
+type Query {
+	films: [Film!]
+}
+
+type Film {
+	title: String!
+}
+`
+
+type rateLimitRoot struct{}
+
+func (rateLimitRoot) Films() *[]*rateLimitFilm { return &[]*rateLimitFilm{{}} }
+
+type rateLimitFilm struct{}
+
+func (*rateLimitFilm) Title() string { return "A New Hope" }
+
+func TestRateLimits(t *testing.T) {
+	costs, err := ratelimit.NewCosts(rateLimitSchema)
+	require.NoError(t, err)
+
+	keys, err := auth.ParseAPIKeys(strings.NewReader("staff-key ops internal"))
+	require.NoError(t, err)
+
+	h := handler.GraphQL{
+		Schema:        graphql.MustParseSchema(rateLimitSchema, &rateLimitRoot{}),
+		Authenticator: keys,
+		RateLimits: &ratelimit.Limiter{
+			Store: ratelimit.NewMemory(),
+			Costs: costs,
+			Anonymous: ratelimit.Tier{
+				Requests: ratelimit.Limit{Capacity: 3, Per: time.Minute},
+				Cost:     ratelimit.Limit{Capacity: 25, Per: time.Minute},
+			},
+			Tiers: map[string]ratelimit.Tier{"internal": {}},
+		},
+	}
+
+	addr := "192.0.2.1:1234"
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		r.RemoteAddr = addr
+
+		if key != "" {
+			r.Header.Set(auth.APIKeyHeader, key)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w
+	}
+
+	films := `{"query": "{ films { title } }"}` // Costs 10.
+
+	w := post("", films)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `3;w=60;comment="requests", 25;w=60;comment="cost"`, w.Header().Get("RateLimit-Policy"))
+	// The headers describe the budget closest to running out.
+	require.Equal(t, "25", w.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "15", w.Header().Get("RateLimit-Remaining"))
+
+	w = post("", films)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "25", w.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "5", w.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "48", w.Header().Get("RateLimit-Reset"))
+
+	w = post("", films)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "12", w.Header().Get("Retry-After"))
+	require.JSONEq(t, `{"error": "rate limit exceeded"}`, w.Body.String())
+
+	// The request budget is spent, even by operations which cost nothing.
+	w = post("", `{"query": "{ __typename }"}`)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	// Operations which could never fit in the budget are rejected outright.
+	w = post("", `{"query": "{ a: films { title } b: films { title } c: films { title } }"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.JSONEq(t, `{"error": "query cost 30 exceeds the limit of 25"}`, w.Body.String())
+
+	// Principals are limited separately from their IP address, by their tier.
+	for i := 0; i < 5; i++ {
+		w = post("staff-key", films)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+
+	// Failed authentication attempts are spent from the budget of the IP address, so keys cannot
+	// be guessed without limit.
+	w = post("guessed-key", films)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	addr = "192.0.2.2:1234"
+
+	for i := 0; i < 3; i++ {
+		w = post("guessed-key", films)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	w = post("guessed-key", films)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = post("", films)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+}
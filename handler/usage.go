@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tonyghita/graphql-go-example/usage"
+)
+
+// Clients identify themselves with the headers sent by apollo-client and Apollo's mobile clients.
+const (
+	clientNameHeader    = "apollographql-client-name"
+	clientVersionHeader = "apollographql-client-version"
+)
+
+// recordUsage records the schema coordinates used by each operation of the request.
+func (h GraphQL) recordUsage(r *http.Request, queries []query) {
+	if h.Usage == nil {
+		return
+	}
+
+	c := usage.Client{Name: r.Header.Get(clientNameHeader), Version: r.Header.Get(clientVersionHeader)}
+	if c.Name == "" {
+		c.Name = usage.UnknownClient
+	}
+
+	now := time.Now()
+
+	for _, q := range queries {
+		// Operations which cannot be parsed fail validation, and use nothing.
+		_ = h.Usage.Record(c, q.Query, q.OpName, now)
+	}
+}
+
+// The Usage handler reports how often each client uses each part of the schema.
+//
+// The report can be narrowed with query parameters:
+//
+//	coordinate  a coordinate, such as "Film.directorName", or a type, such as "Film"
+//	deprecated  "true" to report only deprecated coordinates
+//	unusedFor   report only coordinates no client has used for this many days
+//	days        count only the last this many days
+type Usage struct {
+	Tracker *usage.Tracker
+}
+
+func (h Usage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		respond(w, errorJSON("only GET requests are supported"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	f := usage.Filter{Coordinate: q.Get("coordinate")}
+
+	var err error
+
+	if v := q.Get("deprecated"); v != "" {
+		if f.Deprecated, err = strconv.ParseBool(v); err != nil {
+			respond(w, errorJSON("deprecated must be true or false"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	for name, dst := range map[string]*int{"unusedFor": &f.UnusedFor, "days": &f.Days} {
+		v := q.Get(name)
+		if v == "" {
+			continue
+		}
+
+		if *dst, err = strconv.Atoi(v); err != nil || *dst < 0 {
+			respond(w, errorJSON(name+" must be a number of days"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	b, err := json.Marshal(struct {
+		Coordinates []usage.Usage `json:"coordinates"`
+	}{h.Tracker.Report(f, time.Now())})
+	if err != nil {
+		respond(w, errorJSON("server error"), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respond(w, b, http.StatusOK)
+}
@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/handler"
+	"github.com/tonyghita/graphql-go-example/usage"
+)
+
+const usageSchema = `
+schema { query: Query }
+
+type Query {
+	greeting: String
+	salutation: String @deprecated(reason: "Use greeting.")
+}
+`
+
+type usageRoot struct{}
+
+func (usageRoot) Greeting() *string   { return nil }
+func (usageRoot) Salutation() *string { return nil }
+
+func TestUsage(t *testing.T) {
+	idx, err := usage.NewIndex(usageSchema)
+	require.NoError(t, err)
+
+	tracker, err := usage.Open(idx, "", 0, 0)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(handler.GraphQL{
+		Schema: graphql.MustParseSchema(usageSchema, &usageRoot{}),
+		Usage:  tracker,
+	})
+	t.Cleanup(ts.Close)
+
+	get(t, ts, `{ salutation }`, http.Header{"Apollographql-Client-Name": {"ios"}, "Apollographql-Client-Version": {"1.2"}})
+	get(t, ts, `{ greeting }`, nil)
+
+	report := func(query string) (int, []usage.Usage) {
+		rec := httptest.NewRecorder()
+		handler.Usage{Tracker: tracker}.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/usage?"+query, nil))
+
+		var body struct{ Coordinates []usage.Usage }
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		}
+
+		return rec.Code, body.Coordinates
+	}
+
+	code, coords := report("deprecated=true")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, coords, 1)
+	require.Equal(t, "Query.salutation", coords[0].Coordinate)
+	require.Len(t, coords[0].Clients, 1)
+	require.Equal(t, "ios", coords[0].Clients[0].Name)
+	require.Equal(t, "1.2", coords[0].Clients[0].Version)
+
+	_, coords = report("coordinate=Query.greeting")
+	require.Len(t, coords, 1)
+	require.Equal(t, "unknown", coords[0].Clients[0].Name)
+
+	_, coords = report("deprecated=true&unusedFor=7")
+	require.Empty(t, coords)
+
+	code, _ = report("days=-1")
+	require.Equal(t, http.StatusBadRequest, code)
+}
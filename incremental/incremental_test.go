@@ -0,0 +1,134 @@
+package incremental_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/incremental"
+)
+
+func TestSplitNothingDeferred(t *testing.T) {
+	plan, err := incremental.Split(`{ films { title } }`, "", nil)
+	require.NoError(t, err)
+	require.Nil(t, plan)
+
+	plan, err = incremental.Split(`query Q($d: Boolean) { films { ... @defer(if: $d) { title } } }`, "", map[string]interface{}{"d": false})
+	require.NoError(t, err)
+	require.Nil(t, plan)
+}
+
+func TestSplitDefer(t *testing.T) {
+	plan, err := incremental.Split(`
+		query Film($title: String, $unit: LengthUnit) {
+			films(title: $title) {
+				title
+				...Relations @defer(label: "relations")
+			}
+		}
+
+		fragment Relations on Film {
+			characters { name height(unit: $unit) }
+		}
+	`, "Film", nil)
+	require.NoError(t, err)
+
+	require.Equal(t, `query Film($title:String){films(title:$title){title}}`, plan.Query)
+	require.Len(t, plan.Parts, 1)
+	require.Equal(t,
+		`query Film($title:String$unit:LengthUnit){films(title:$title){...on Film{characters{name height(unit:$unit)}}}}`,
+		plan.Parts[0].Query)
+
+	payload, err := plan.Parts[0].Payload(&graphql.Response{
+		Data: json.RawMessage(`{"films":[{"characters":[{"name":"Luke","height":1.72}]},{"characters":null}]}`),
+		Errors: []*gqlerrors.QueryError{
+			{Message: "not found", Path: []interface{}{"films", 1, "characters"}},
+			{Message: "elsewhere", Path: []interface{}{"other"}},
+		},
+	})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(payload)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"incremental": [
+			{"data": {"characters": [{"name": "Luke", "height": 1.72}]}, "path": ["films", 0], "label": "relations"},
+			{"data": {"characters": null}, "path": ["films", 1], "label": "relations",
+			 "errors": [{"message": "not found", "path": ["films", 1, "characters"]}]}
+		],
+		"errors": [{"message": "elsewhere", "path": ["other"]}],
+		"hasNext": false
+	}`, string(b))
+}
+
+func TestSplitEmptySelection(t *testing.T) {
+	plan, err := incremental.Split(`{ films { ... @defer { title } } }`, "", nil)
+	require.NoError(t, err)
+
+	require.Equal(t, `query{films{_incremental:__typename}}`, plan.Query)
+
+	initial, rest, err := plan.Initial(&graphql.Response{
+		Data: json.RawMessage(`{"films":[{"_incremental":"Film"}]}`),
+	})
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.JSONEq(t, `{"films":[{}]}`, string(initial.Data))
+}
+
+func TestSplitStream(t *testing.T) {
+	plan, err := incremental.Split(`{
+		films {
+			title
+			characters @stream(label: "people") { name }
+			producerNames @stream(initialCount: 1)
+		}
+	}`, "", nil)
+	require.NoError(t, err)
+
+	require.Equal(t, `query{films{title characters{_incremental:__typename}producerNames}}`, plan.Query)
+	require.Len(t, plan.Parts, 1)
+	require.Equal(t, `query{films{characters{name}}}`, plan.Parts[0].Query)
+
+	initial, rest, err := plan.Initial(&graphql.Response{
+		Data: json.RawMessage(`{"films":[{"title":"A New Hope","characters":[{"_incremental":"Person"},{"_incremental":"Person"}],"producerNames":["Gary Kurtz","Rick McCallum"]}]}`),
+		Errors: []*gqlerrors.QueryError{
+			{Message: "bad name", Path: []interface{}{"films", 0, "producerNames", 1}},
+		},
+	})
+	require.NoError(t, err)
+
+	// Keys keep the order they were selected in.
+	require.Equal(t, `{"films":[{"title":"A New Hope","characters":[],"producerNames":["Gary Kurtz"]}]}`, string(initial.Data))
+	require.Empty(t, initial.Errors)
+
+	require.Len(t, rest, 1)
+	require.Equal(t, []interface{}{"films", 0, "producerNames", 1}, rest[0].Path)
+	require.Len(t, rest[0].Errors, 1)
+
+	payload, err := plan.Parts[0].Payload(&graphql.Response{
+		Data: json.RawMessage(`{"films":[{"characters":[{"name":"Luke"},{"name":"Leia"}]}]}`),
+	})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(payload)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"incremental": [{"items": [{"name": "Luke"}, {"name": "Leia"}], "path": ["films", 0, "characters", 0], "label": "people"}],
+		"hasNext": false
+	}`, string(b))
+}
+
+func TestSplitInvalid(t *testing.T) {
+	for _, q := range []string{
+		`{ films { ...Missing @defer } }`,
+		`{ films { ...A } } fragment A on Film { ...A @defer }`,
+		`query A { a } query B { b @stream }`,
+		`{ films @stream `,
+	} {
+		_, err := incremental.Split(q, "", nil)
+		require.Error(t, err, q)
+	}
+}
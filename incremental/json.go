@@ -0,0 +1,46 @@
+package incremental
+
+import "github.com/tonyghita/graphql-go-example/ordered"
+
+// walk calls fn for every value found by following the response keys from v.
+// Lists met along the way are expanded, and the index of each item is added to the path. The list
+// at the end of the keys is expanded as well when expandLast is true.
+// set replaces the value in its parent; it is nil for the value the walk started from.
+func walk(v interface{}, keys []string, path []interface{}, expandLast bool, set func(interface{}), fn func(v interface{}, path []interface{}, set func(interface{}))) {
+	if l, ok := v.([]interface{}); ok && (len(keys) > 0 || expandLast) {
+		for i, item := range l {
+			i := i
+			walk(item, keys, extend(path, i), expandLast, func(v interface{}) { l[i] = v }, fn)
+		}
+
+		return
+	}
+
+	if len(keys) == 0 {
+		fn(v, path, set)
+		return
+	}
+
+	o, ok := v.(*ordered.Object)
+	if !ok {
+		return // A null parent, or a value which is not an object.
+	}
+
+	key := keys[0]
+
+	child, ok := o.Get(key)
+	if !ok {
+		return
+	}
+
+	walk(child, keys[1:], extend(path, key), expandLast, func(v interface{}) { o.Set(key, v) }, fn)
+}
+
+// extend returns a copy of the path with the element appended, so sibling paths never share a
+// backing array.
+func extend(path []interface{}, elem interface{}) []interface{} {
+	p := make([]interface{}, len(path), len(path)+1)
+	copy(p, path)
+
+	return append(p, elem)
+}
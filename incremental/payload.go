@@ -0,0 +1,150 @@
+package incremental
+
+import (
+	"encoding/json"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/tonyghita/graphql-go-example/ordered"
+)
+
+// A Payload is one part of an incrementally delivered response.
+type Payload struct {
+	Data        json.RawMessage         `json:"data,omitempty"`
+	Errors      []*gqlerrors.QueryError `json:"errors,omitempty"`
+	Incremental []*Result               `json:"incremental,omitempty"`
+	Extensions  map[string]interface{}  `json:"extensions,omitempty"`
+	HasNext     bool                    `json:"hasNext"`
+}
+
+// A Result holds the data of a deferred fragment, or items of a streamed list, at a path.
+type Result struct {
+	Data   interface{}             `json:"data,omitempty"`
+	Items  []interface{}           `json:"items,omitempty"`
+	Path   []interface{}           `json:"path"`
+	Label  string                  `json:"label,omitempty"`
+	Errors []*gqlerrors.QueryError `json:"errors,omitempty"`
+}
+
+// Initial returns the initial payload from the result of the initial query.
+// Streamed lists are truncated to their initial count; the items removed from lists whose remaining
+// items are not resolved by a Part are returned as results, to be delivered next.
+func (p *Plan) Initial(res *graphql.Response) (*Payload, []*Result, error) {
+	data, err := ordered.Decode(res.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rest []*Result
+
+	for _, st := range p.streams {
+		st := st
+
+		walk(data, st.keys, nil, false, nil, func(v interface{}, path []interface{}, set func(interface{})) {
+			l, ok := v.([]interface{})
+			if !ok || len(l) <= st.count {
+				return
+			}
+
+			set(l[:st.count])
+
+			if st.rest {
+				rest = append(rest, &Result{Items: l[st.count:], Path: extend(path, st.count), Label: st.label})
+			}
+		})
+	}
+
+	ordered.RemoveAll(data, placeholder)
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Errors of truncated items are delivered along with the items.
+	errs := assign(res.Errors, rest)
+
+	return &Payload{Data: b, Errors: errs, Extensions: res.Extensions}, rest, nil
+}
+
+// Payload returns the subsequent payload from the result of the part's query.
+func (p *Part) Payload(res *graphql.Response) (*Payload, error) {
+	data, err := ordered.Decode(res.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered.RemoveAll(data, placeholder)
+
+	var results []*Result
+
+	walk(data, p.keys, nil, !p.stream, nil, func(v interface{}, path []interface{}, _ func(interface{})) {
+		if !p.stream {
+			if o, ok := v.(*ordered.Object); ok && len(o.Keys()) > 0 {
+				results = append(results, &Result{Data: o, Path: path, Label: p.label})
+			}
+
+			return
+		}
+
+		if l, ok := v.([]interface{}); ok && len(l) > 0 {
+			results = append(results, &Result{Items: l, Path: extend(path, 0), Label: p.label})
+		}
+	})
+
+	return &Payload{Incremental: results, Errors: assign(res.Errors, results), Extensions: res.Extensions}, nil
+}
+
+// assign moves every error whose path lies within a result onto the result, and returns the
+// remaining errors.
+func assign(errs []*gqlerrors.QueryError, results []*Result) []*gqlerrors.QueryError {
+	var rest []*gqlerrors.QueryError
+
+	for _, err := range errs {
+		if r := within(err.Path, results); r != nil {
+			r.Errors = append(r.Errors, err)
+			continue
+		}
+
+		rest = append(rest, err)
+	}
+
+	return rest
+}
+
+// within returns the result whose data or items contain the path, or nil.
+func within(path []interface{}, results []*Result) *Result {
+	for _, r := range results {
+		prefix := r.Path
+		if r.Items != nil {
+			// Items start at the last element of the path; match any item from there on.
+			prefix = r.Path[:len(r.Path)-1]
+		}
+
+		if len(path) <= len(prefix) || !equal(path[:len(prefix)], prefix) {
+			continue
+		}
+
+		if r.Items == nil {
+			return r
+		}
+
+		first, _ := r.Path[len(r.Path)-1].(int)
+		if i, ok := path[len(prefix)].(int); ok && i >= first && i < first+len(r.Items) {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func equal(a, b []interface{}) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return len(a) == len(b)
+}
@@ -0,0 +1,383 @@
+// Package incremental delivers the results of an operation incrementally, as directed by the
+// @defer and @stream directives.
+//
+// graphql-go executes an operation as a whole, so this package splits an operation into an initial
+// query, which excludes deferred fragments and streamed items, and one query per deferred fragment
+// or streamed field. Each query selects its fragment or field below the same parent fields as the
+// original operation. Because those queries run against the same per-request loaders, the parent
+// fields are loaded once and shared. The results of the queries are then turned into the payloads
+// of the incremental delivery format.
+package incremental
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tonyghita/graphql-go-example/language"
+)
+
+// placeholder is the alias of the __typename field selected in place of selection sets which
+// would otherwise be empty. It is removed from the results.
+const placeholder = "_incremental"
+
+// A Plan describes how to execute an operation incrementally.
+type Plan struct {
+	// Query is the query which produces the initial payload.
+	Query string
+	// Parts are the queries which produce the subsequent payloads.
+	Parts []*Part
+
+	streams []stream
+}
+
+// A Part is a query which produces the data of a deferred fragment or the items of a streamed
+// field.
+type Part struct {
+	Query string
+
+	label  string
+	keys   []string // The response keys from the root to the fragment's object or the streamed list.
+	stream bool
+}
+
+// A stream is a streamed field whose list is truncated in the initial payload.
+type stream struct {
+	label string
+	keys  []string
+	count int
+	// rest is true when the initial query resolves the whole list, so the remaining items are
+	// taken from the initial result rather than from a Part.
+	rest bool
+}
+
+// Split plans the incremental execution of an operation.
+// It returns nil when the operation defers nothing. It returns an error when the document cannot
+// be planned, in which case it should be executed as a whole, so graphql-go reports any problem.
+func Split(query, operationName string, variables map[string]interface{}) (*Plan, error) {
+	if !strings.Contains(query, "@defer") && !strings.Contains(query, "@stream") {
+		return nil, nil
+	}
+
+	doc, err := language.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	op := doc.Operation(operationName)
+	if op == nil {
+		return nil, fmt.Errorf("operation %q not found", operationName)
+	}
+
+	if op.Type == "subscription" {
+		return nil, nil
+	}
+
+	sels, err := inline(doc, op.SelectionSet, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &splitter{op: op, vars: variables}
+	initial := s.split(sels, nil, nil)
+
+	if len(s.parts) == 0 && len(s.streams) == 0 {
+		return nil, nil
+	}
+
+	return &Plan{Query: s.print(initial), Parts: s.parts, streams: s.streams}, nil
+}
+
+// inline replaces fragment spreads with equivalent inline fragments, so every selection of the
+// operation has a single path from the root.
+func inline(doc *language.Document, sels []language.Selection, visiting map[string]bool) ([]language.Selection, error) {
+	out := make([]language.Selection, 0, len(sels))
+
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *language.Field:
+			f := *sel
+
+			if len(sel.SelectionSet) > 0 {
+				var err error
+				if f.SelectionSet, err = inline(doc, sel.SelectionSet, visiting); err != nil {
+					return nil, err
+				}
+			}
+
+			out = append(out, &f)
+		case *language.InlineFragment:
+			f := *sel
+
+			var err error
+			if f.SelectionSet, err = inline(doc, sel.SelectionSet, visiting); err != nil {
+				return nil, err
+			}
+
+			out = append(out, &f)
+		case *language.FragmentSpread:
+			def := doc.Fragment(sel.Name)
+			if def == nil {
+				return nil, fmt.Errorf("fragment %q not found", sel.Name)
+			}
+
+			if visiting[sel.Name] {
+				return nil, fmt.Errorf("fragment %q spreads itself", sel.Name)
+			}
+
+			visiting[sel.Name] = true
+			inlined, err := inline(doc, def.SelectionSet, visiting)
+			delete(visiting, sel.Name)
+
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, &language.InlineFragment{
+				TypeCondition: def.TypeCondition,
+				Directives:    sel.Directives,
+				SelectionSet:  inlined,
+			})
+		}
+	}
+
+	return out, nil
+}
+
+type splitter struct {
+	op      *language.Operation
+	vars    map[string]interface{}
+	parts   []*Part
+	streams []stream
+}
+
+// split returns the selections to execute in the initial query, and records a part for every
+// deferred fragment and streamed field.
+// ancestors are the fields and inline fragments enclosing the selections, and keys are the response
+// keys of the enclosing fields.
+func (s *splitter) split(sels []language.Selection, ancestors []language.Selection, keys []string) []language.Selection {
+	var out []language.Selection
+
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *language.InlineFragment:
+			f := *sel
+			f.Directives = without(sel.Directives, "defer")
+
+			if d := language.FindDirective(sel.Directives, "defer"); d != nil && s.enabled(d) {
+				f.SelectionSet = strip(sel.SelectionSet)
+				s.add(ancestors, &f, keys, s.label(d), false)
+
+				continue
+			}
+
+			f.SelectionSet = s.split(sel.SelectionSet, enclose(ancestors, &f), keys)
+			out = append(out, &f)
+		case *language.Field:
+			f := *sel
+			f.Directives = without(sel.Directives, "stream")
+			path := append(append([]string(nil), keys...), f.ResponseKey())
+
+			if d := language.FindDirective(sel.Directives, "stream"); d != nil && s.enabled(d) {
+				f.SelectionSet = strip(sel.SelectionSet)
+				st := stream{label: s.label(d), keys: path, count: s.initialCount(d)}
+
+				if st.count > 0 || len(f.SelectionSet) == 0 {
+					// The first items must be resolved for the initial payload anyway, and scalars are
+					// cheap, so resolve the whole list at once.
+					st.rest = true
+					out = append(out, &f)
+				} else {
+					s.add(ancestors, &f, path, st.label, true)
+
+					// The initial payload holds an empty list, or null; select as little as possible.
+					initial := f
+					initial.SelectionSet = placeholderSelection()
+					out = append(out, &initial)
+				}
+
+				s.streams = append(s.streams, st)
+
+				continue
+			}
+
+			if len(sel.SelectionSet) > 0 {
+				f.SelectionSet = s.split(sel.SelectionSet, enclose(ancestors, &f), path)
+			}
+
+			out = append(out, &f)
+		}
+	}
+
+	if len(out) == 0 {
+		return placeholderSelection()
+	}
+
+	return out
+}
+
+// add records a part which selects the target below the ancestors. keys lead to the object the
+// target is selected on or, for a streamed field, to the list itself.
+func (s *splitter) add(ancestors []language.Selection, target language.Selection, keys []string, label string, isStream bool) {
+	sel := target
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		switch a := ancestors[i].(type) {
+		case *language.Field:
+			f := *a
+			f.SelectionSet = []language.Selection{sel}
+			sel = &f
+		case *language.InlineFragment:
+			f := *a
+			f.SelectionSet = []language.Selection{sel}
+			sel = &f
+		}
+	}
+
+	s.parts = append(s.parts, &Part{
+		Query:  s.print([]language.Selection{sel}),
+		label:  label,
+		keys:   append([]string(nil), keys...),
+		stream: isStream,
+	})
+}
+
+// print returns a query with the selections, declaring only the variables they use.
+func (s *splitter) print(sels []language.Selection) string {
+	used := map[string]bool{}
+	variables(sels, used)
+	directiveVariables(s.op.Directives, used)
+
+	op := &language.Operation{Type: s.op.Type, Name: s.op.Name, Directives: s.op.Directives, SelectionSet: sels}
+
+	for _, v := range s.op.Variables {
+		if used[v.Name] {
+			op.Variables = append(op.Variables, v)
+		}
+	}
+
+	return language.Print(&language.Document{Operations: []*language.Operation{op}})
+}
+
+// enabled reports whether the directive applies, according to its "if" argument.
+func (s *splitter) enabled(d *language.Directive) bool {
+	a := d.Argument("if")
+	if a == nil {
+		return true
+	}
+
+	v, ok := a.Value.Resolve(s.vars).(bool)
+	return !ok || v
+}
+
+func (s *splitter) label(d *language.Directive) string {
+	if a := d.Argument("label"); a != nil {
+		if l, ok := a.Value.Resolve(s.vars).(string); ok {
+			return l
+		}
+	}
+
+	return ""
+}
+
+func (s *splitter) initialCount(d *language.Directive) int {
+	if a := d.Argument("initialCount"); a != nil {
+		if n, ok := a.Value.Resolve(s.vars).(float64); ok && n > 0 {
+			return int(n)
+		}
+	}
+
+	return 0
+}
+
+// strip removes @defer and @stream from the selections, so they are delivered at once.
+func strip(sels []language.Selection) []language.Selection {
+	if len(sels) == 0 {
+		return sels
+	}
+
+	out := make([]language.Selection, len(sels))
+
+	for i, sel := range sels {
+		switch sel := sel.(type) {
+		case *language.Field:
+			f := *sel
+			f.Directives = without(sel.Directives, "stream")
+			f.SelectionSet = strip(sel.SelectionSet)
+			out[i] = &f
+		case *language.InlineFragment:
+			f := *sel
+			f.Directives = without(sel.Directives, "defer")
+			f.SelectionSet = strip(sel.SelectionSet)
+			out[i] = &f
+		default:
+			out[i] = sel
+		}
+	}
+
+	return out
+}
+
+// enclose returns a copy of the ancestors with the selection appended.
+func enclose(ancestors []language.Selection, sel language.Selection) []language.Selection {
+	a := make([]language.Selection, len(ancestors), len(ancestors)+1)
+	copy(a, ancestors)
+
+	return append(a, sel)
+}
+
+func without(dirs []*language.Directive, name string) []*language.Directive {
+	var out []*language.Directive
+
+	for _, d := range dirs {
+		if d.Name != name {
+			out = append(out, d)
+		}
+	}
+
+	return out
+}
+
+func placeholderSelection() []language.Selection {
+	return []language.Selection{&language.Field{Alias: placeholder, Name: "__typename"}}
+}
+
+// variables records the names of the variables used by the selections.
+func variables(sels []language.Selection, used map[string]bool) {
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *language.Field:
+			for _, a := range sel.Arguments {
+				valueVariables(a.Value, used)
+			}
+
+			directiveVariables(sel.Directives, used)
+			variables(sel.SelectionSet, used)
+		case *language.InlineFragment:
+			directiveVariables(sel.Directives, used)
+			variables(sel.SelectionSet, used)
+		}
+	}
+}
+
+func directiveVariables(dirs []*language.Directive, used map[string]bool) {
+	for _, d := range dirs {
+		for _, a := range d.Arguments {
+			valueVariables(a.Value, used)
+		}
+	}
+}
+
+func valueVariables(v *language.Value, used map[string]bool) {
+	switch v.Kind {
+	case language.VariableValue:
+		used[v.Text] = true
+	case language.ListValue:
+		for _, item := range v.List {
+			valueVariables(item, used)
+		}
+	case language.ObjectValue:
+		for _, f := range v.Fields {
+			valueVariables(f.Value, used)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package language
+
+import "strconv"
+
+// A Document is a parsed executable GraphQL document: a query and the fragments it uses.
+type Document struct {
+	Operations []*Operation
+	Fragments  []*Fragment
+}
+
+// Operation returns the operation with the given name, or the only operation of the document when
+// the name is empty. It returns nil when there is no such operation.
+func (d *Document) Operation(name string) *Operation {
+	if name == "" {
+		if len(d.Operations) == 1 {
+			return d.Operations[0]
+		}
+
+		return nil
+	}
+
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op
+		}
+	}
+
+	return nil
+}
+
+// Fragment returns the fragment definition with the given name, or nil.
+func (d *Document) Fragment(name string) *Fragment {
+	for _, f := range d.Fragments {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// An Operation is a query, mutation or subscription.
+type Operation struct {
+	// Type is "query", "mutation" or "subscription".
+	Type         string
+	Name         string
+	Variables    []*VariableDefinition
+	Directives   []*Directive
+	SelectionSet []Selection
+}
+
+// A VariableDefinition declares a variable of an operation.
+type VariableDefinition struct {
+	Name       string
+	Type       string // The type as written, such as "[ID!]!".
+	Default    *Value
+	Directives []*Directive
+}
+
+// A Fragment is a named fragment definition.
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+// A Selection is a *Field, *FragmentSpread or *InlineFragment.
+type Selection interface {
+	selection()
+}
+
+// A Field selects a field of an object.
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+}
+
+// ResponseKey returns the key of the field in the response: its alias, or otherwise its name.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+
+	return f.Name
+}
+
+// A FragmentSpread includes a named fragment.
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+// An InlineFragment includes selections, optionally only for objects of a type.
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+func (*Field) selection()          {}
+func (*FragmentSpread) selection() {}
+func (*InlineFragment) selection() {}
+
+// A Directive annotates part of a document, such as @include(if: $x).
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+}
+
+// An Argument is a named value passed to a field or directive.
+type Argument struct {
+	Name  string
+	Value *Value
+}
+
+// Argument returns the argument with the given name, or nil.
+func (d *Directive) Argument(name string) *Argument {
+	return argument(d.Arguments, name)
+}
+
+// Argument returns the argument with the given name, or nil.
+func (f *Field) Argument(name string) *Argument {
+	return argument(f.Arguments, name)
+}
+
+func argument(args []*Argument, name string) *Argument {
+	for _, a := range args {
+		if a.Name == name {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// FindDirective returns the directive with the given name, or nil.
+func FindDirective(dirs []*Directive, name string) *Directive {
+	for _, d := range dirs {
+		if d.Name == name {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// A ValueKind classifies a Value.
+type ValueKind int
+
+const (
+	VariableValue ValueKind = iota
+	IntValue
+	FloatValue
+	StringValue
+	BooleanValue
+	NullValue
+	EnumValue
+	ListValue
+	ObjectValue
+)
+
+// A Value is an input value, either a literal or a variable.
+type Value struct {
+	Kind ValueKind
+	// Text is the name of a variable or enum value, the text of a number, the unquoted string,
+	// or "true" or "false".
+	Text   string
+	List   []*Value       // The items of a list.
+	Fields []*ObjectField // The fields of an object.
+}
+
+// An ObjectField is a field of an input object value.
+type ObjectField struct {
+	Name  string
+	Value *Value
+}
+
+// Resolve returns the Go value of the input value, looking variables up in vars.
+// Numbers are returned as float64, matching values decoded from JSON.
+func (v *Value) Resolve(vars map[string]interface{}) interface{} {
+	switch v.Kind {
+	case VariableValue:
+		return vars[v.Text]
+	case IntValue, FloatValue:
+		f, _ := strconv.ParseFloat(v.Text, 64)
+		return f
+	case StringValue, EnumValue:
+		return v.Text
+	case BooleanValue:
+		return v.Text == "true"
+	case ListValue:
+		l := make([]interface{}, len(v.List))
+		for i, item := range v.List {
+			l[i] = item.Resolve(vars)
+		}
+		return l
+	case ObjectValue:
+		m := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			m[f.Name] = f.Value.Resolve(vars)
+		}
+		return m
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+// Package language reads GraphQL documents.
+//
+// graphql-go parses queries and schemas internally but does not expose its syntax trees, so the
+// features of this API which need to inspect documents before or after execution use this package.
+package language
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Kind classifies a Token.
+type Kind int
+
+const (
+	EOF    Kind = iota
+	Punct       // Punctuation, such as "{", "!" or "...".
+	Name        // A name or keyword.
+	Int         // An integer literal.
+	Float       // A float literal.
+	String      // A string or block string literal. Its text is the unquoted value.
+)
+
+// A Token is a lexical token of a GraphQL document.
+type Token struct {
+	Kind Kind
+	Text string
+	Line int
+	// Block is true for a block string ("""...""").
+	Block bool
+}
+
+// A Lexer splits a GraphQL document into tokens.
+// Whitespace, commas and comments are insignificant and skipped.
+type Lexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+// NewLexer creates a Lexer for the document.
+func NewLexer(src string) *Lexer {
+	return &Lexer{src: src, line: 1}
+}
+
+// Line returns the line of the document the Lexer has reached.
+func (l *Lexer) Line() int {
+	return l.line
+}
+
+// Next returns the next token, or a token of kind EOF at the end of the document.
+func (l *Lexer) Next() (Token, error) {
+	l.skipIgnored()
+
+	if l.pos >= len(l.src) {
+		return Token{Kind: EOF, Line: l.line}, nil
+	}
+
+	start, line := l.pos, l.line
+	c := l.src[l.pos]
+
+	switch {
+	case c == '.':
+		if !strings.HasPrefix(l.src[l.pos:], "...") {
+			return Token{}, fmt.Errorf("line %d: unexpected %q", line, c)
+		}
+
+		l.pos += 3
+
+		return Token{Kind: Punct, Text: "...", Line: line}, nil
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		l.pos++
+		return Token{Kind: Punct, Text: string(c), Line: line}, nil
+	case isNameStart(c):
+		for l.pos < len(l.src) && isNameContinue(l.src[l.pos]) {
+			l.pos++
+		}
+
+		return Token{Kind: Name, Text: l.src[start:l.pos], Line: line}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.blockString()
+		}
+
+		return l.string()
+	}
+
+	return Token{}, fmt.Errorf("line %d: unexpected %q", line, c)
+}
+
+// All returns every token of the document, excluding the final EOF token.
+func (l *Lexer) All() ([]Token, error) {
+	var toks []Token
+
+	for {
+		t, err := l.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if t.Kind == EOF {
+			return toks, nil
+		}
+
+		toks = append(toks, t)
+	}
+}
+
+func (l *Lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; c {
+		case '\n':
+			l.line++
+			l.pos++
+		case ' ', '\t', '\r', ',':
+			l.pos++
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *Lexer) number() (Token, error) {
+	start, kind := l.pos, Int
+
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+
+	l.digits()
+
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = Float
+		l.pos++
+		l.digits()
+	}
+
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = Float
+		l.pos++
+
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+
+		l.digits()
+	}
+
+	text := l.src[start:l.pos]
+	if text == "-" {
+		return Token{}, fmt.Errorf("line %d: invalid number", l.line)
+	}
+
+	return Token{Kind: kind, Text: text, Line: l.line}, nil
+}
+
+func (l *Lexer) digits() {
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *Lexer) string() (Token, error) {
+	line := l.line
+	l.pos++ // Opening quote.
+
+	var b strings.Builder
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		switch c {
+		case '"':
+			l.pos++
+			return Token{Kind: String, Text: b.String(), Line: line}, nil
+		case '\n':
+			return Token{}, fmt.Errorf("line %d: unterminated string", line)
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return Token{}, fmt.Errorf("line %d: unterminated string", line)
+			}
+
+			l.pos++
+
+			switch e := l.src[l.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if l.pos+4 >= len(l.src) {
+					return Token{}, fmt.Errorf("line %d: invalid unicode escape", line)
+				}
+
+				var r rune
+				if _, err := fmt.Sscanf(l.src[l.pos+1:l.pos+5], "%04x", &r); err != nil {
+					return Token{}, fmt.Errorf("line %d: invalid unicode escape", line)
+				}
+
+				b.WriteRune(r)
+				l.pos += 4
+			default:
+				b.WriteByte(e)
+			}
+
+			l.pos++
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+
+	return Token{}, fmt.Errorf("line %d: unterminated string", line)
+}
+
+func (l *Lexer) blockString() (Token, error) {
+	line := l.line
+	l.pos += 3
+
+	end := strings.Index(l.src[l.pos:], `"""`)
+	for end >= 0 && end > 0 && l.src[l.pos+end-1] == '\\' {
+		next := strings.Index(l.src[l.pos+end+3:], `"""`)
+		if next < 0 {
+			end = -1
+			break
+		}
+
+		end += 3 + next
+	}
+
+	if end < 0 {
+		return Token{}, fmt.Errorf("line %d: unterminated block string", line)
+	}
+
+	raw := l.src[l.pos : l.pos+end]
+	l.line += strings.Count(raw, "\n")
+	l.pos += end + 3
+
+	return Token{Kind: String, Text: BlockStringValue(strings.ReplaceAll(raw, `\"""`, `"""`)), Line: line, Block: true}, nil
+}
+
+// BlockStringValue removes the common indentation and the leading and trailing blank lines of a
+// block string, as defined by the GraphQL specification.
+func BlockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+
+	common := -1
+
+	for i, line := range lines {
+		if i == 0 {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < len(line) && (common < 0 || indent < common) {
+			common = indent
+		}
+	}
+
+	if common > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= common {
+				lines[i] = lines[i][common:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,96 @@
+package language_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/language"
+)
+
+func TestLexer(t *testing.T) {
+	toks, err := language.NewLexer(`
+		# A comment.
+		query Q($n: Int = -1.5e3) {
+			films(title: "A \"New\" Hope!", ids: [1, 2]) { ...F }
+		}
+		"""
+		  Block
+		    string
+		"""`).All()
+	require.NoError(t, err)
+
+	var texts []string
+	for _, tok := range toks {
+		texts = append(texts, tok.Text)
+	}
+
+	require.Equal(t, []string{
+		"query", "Q", "(", "$", "n", ":", "Int", "=", "-1.5e3", ")", "{",
+		"films", "(", "title", ":", `A "New" Hope!`, "ids", ":", "[", "1", "2", "]", ")", "{", "...", "F", "}",
+		"}",
+		"Block\n  string",
+	}, texts)
+
+	require.Equal(t, language.Float, toks[8].Kind)
+	require.Equal(t, 4, toks[11].Line)
+	require.True(t, toks[len(toks)-1].Block)
+}
+
+func TestLexerErrors(t *testing.T) {
+	for _, src := range []string{`"unterminated`, `..`, `"""open`, `?`} {
+		_, err := language.NewLexer(src).All()
+		require.Error(t, err, src)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a, err := language.Normalize("{\n  films(title: \"Hope\") {\n    id, title # comment\n  }\n}")
+	require.NoError(t, err)
+
+	b, err := language.Normalize(`{films(title:"Hope"){id title}}`)
+	require.NoError(t, err)
+
+	require.Equal(t, `{films(title:"Hope"){id title}}`, a)
+	require.Equal(t, a, b)
+}
+
+func TestParsePrint(t *testing.T) {
+	doc, err := language.Parse(`
+		query Film($id: ID!, $first: Int = 10, $tags: [String!]! = ["a", "b"]) @op {
+			film(id: $id) {
+				name: title
+				... on Film @include(if: true) { episode }
+				...Details @defer(label: "details")
+				characters(first: $first, filter: {name: "Luke", exact: false, unit: METER}) @stream(initialCount: 2) { name }
+			}
+		}
+
+		fragment Details on Film { director }
+		{ shorthand }
+	`)
+	require.NoError(t, err)
+
+	require.Len(t, doc.Operations, 2)
+	require.Nil(t, doc.Operation(""))
+	require.NotNil(t, doc.Operation("Film"))
+	require.NotNil(t, doc.Fragment("Details"))
+
+	require.Equal(t,
+		`query Film($id:ID!$first:Int=10$tags:[String!]!=["a" "b"])@op{film(id:$id){name:title...on Film@include(if:true){episode}...Details@defer(label:"details")characters(first:$first filter:{name:"Luke" exact:false unit:METER})@stream(initialCount:2){name}}}`+
+			`query{shorthand}`+
+			`fragment Details on Film{director}`,
+		language.Print(doc))
+
+	// The printed document parses to the same document.
+	again, err := language.Parse(language.Print(doc))
+	require.NoError(t, err)
+	require.Equal(t, doc, again)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{`{`, `{}`, `query Q(`, `fragment F { a }`, `{ a(b: ) }`, `type T { a: Int }`} {
+		_, err := language.Parse(src)
+		require.Error(t, err, src)
+	}
+}
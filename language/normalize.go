@@ -0,0 +1,18 @@
+package language
+
+// Normalize returns a canonical form of a document, in which insignificant whitespace, commas and
+// comments are removed. Documents which differ only in formatting normalize to the same string.
+func Normalize(doc string) (string, error) {
+	toks, err := NewLexer(doc).All()
+	if err != nil {
+		return "", err
+	}
+
+	var p printer
+
+	for _, t := range toks {
+		p.token(t.Kind, t.Text)
+	}
+
+	return p.String(), nil
+}
@@ -0,0 +1,321 @@
+package language
+
+import "fmt"
+
+// Parse parses an executable document: operations and fragment definitions.
+func Parse(doc string) (*Document, error) {
+	toks, err := NewLexer(doc).All()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	d := &Document{}
+
+	for !p.eof() && p.err == nil {
+		switch t := p.peek(); {
+		case t.Kind == Punct && t.Text == "{":
+			d.Operations = append(d.Operations, &Operation{Type: "query", SelectionSet: p.selectionSet()})
+		case t.Kind == Name && t.Text == "fragment":
+			d.Fragments = append(d.Fragments, p.fragment())
+		case t.Kind == Name && (t.Text == "query" || t.Text == "mutation" || t.Text == "subscription"):
+			d.Operations = append(d.Operations, p.operation())
+		default:
+			p.fail("unexpected %q", t.Text)
+		}
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return d, nil
+}
+
+type parser struct {
+	toks []Token
+	pos  int
+	err  error
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *parser) peek() Token {
+	if p.eof() {
+		line := 0
+		if len(p.toks) > 0 {
+			line = p.toks[len(p.toks)-1].Line
+		}
+
+		return Token{Kind: EOF, Line: line}
+	}
+
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() Token {
+	t := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf("line %d: "+format, append([]interface{}{p.peek().Line}, args...)...)
+	}
+
+	// Stop parsing.
+	p.pos = len(p.toks)
+}
+
+func (p *parser) is(punct string) bool {
+	t := p.peek()
+	return t.Kind == Punct && t.Text == punct
+}
+
+func (p *parser) expect(punct string) {
+	if !p.is(punct) {
+		p.fail("expected %q, got %q", punct, p.peek().Text)
+		return
+	}
+
+	p.next()
+}
+
+func (p *parser) name() string {
+	if p.peek().Kind != Name {
+		p.fail("expected a name, got %q", p.peek().Text)
+		return ""
+	}
+
+	return p.next().Text
+}
+
+func (p *parser) operation() *Operation {
+	op := &Operation{Type: p.next().Text}
+
+	if p.peek().Kind == Name {
+		op.Name = p.next().Text
+	}
+
+	if p.is("(") {
+		p.next()
+
+		for !p.is(")") && p.err == nil {
+			op.Variables = append(op.Variables, p.variableDefinition())
+		}
+
+		p.expect(")")
+	}
+
+	op.Directives = p.directives()
+	op.SelectionSet = p.selectionSet()
+
+	return op
+}
+
+func (p *parser) variableDefinition() *VariableDefinition {
+	p.expect("$")
+
+	v := &VariableDefinition{Name: p.name()}
+	p.expect(":")
+	v.Type = p.typeRef()
+
+	if p.is("=") {
+		p.next()
+		v.Default = p.value()
+	}
+
+	v.Directives = p.directives()
+
+	return v
+}
+
+func (p *parser) typeRef() string {
+	var t string
+
+	if p.is("[") {
+		p.next()
+		t = "[" + p.typeRef() + "]"
+		p.expect("]")
+	} else {
+		t = p.name()
+	}
+
+	if p.is("!") {
+		p.next()
+		t += "!"
+	}
+
+	return t
+}
+
+func (p *parser) fragment() *Fragment {
+	p.next()
+
+	f := &Fragment{Name: p.name()}
+
+	if p.peek().Text != "on" {
+		p.fail("expected \"on\", got %q", p.peek().Text)
+		return f
+	}
+
+	p.next()
+	f.TypeCondition = p.name()
+	f.Directives = p.directives()
+	f.SelectionSet = p.selectionSet()
+
+	return f
+}
+
+func (p *parser) selectionSet() []Selection {
+	p.expect("{")
+
+	var sels []Selection
+
+	for !p.is("}") && p.err == nil {
+		sels = append(sels, p.selection())
+	}
+
+	p.expect("}")
+
+	if len(sels) == 0 && p.err == nil {
+		p.fail("empty selection set")
+	}
+
+	return sels
+}
+
+func (p *parser) selection() Selection {
+	if !p.is("...") {
+		return p.field()
+	}
+
+	p.next()
+
+	if t := p.peek(); t.Kind == Name && t.Text != "on" {
+		return &FragmentSpread{Name: p.next().Text, Directives: p.directives()}
+	}
+
+	f := &InlineFragment{}
+
+	if p.peek().Text == "on" {
+		p.next()
+		f.TypeCondition = p.name()
+	}
+
+	f.Directives = p.directives()
+	f.SelectionSet = p.selectionSet()
+
+	return f
+}
+
+func (p *parser) field() *Field {
+	f := &Field{Name: p.name()}
+
+	if p.is(":") {
+		p.next()
+		f.Alias, f.Name = f.Name, p.name()
+	}
+
+	f.Arguments = p.arguments()
+	f.Directives = p.directives()
+
+	if p.is("{") {
+		f.SelectionSet = p.selectionSet()
+	}
+
+	return f
+}
+
+func (p *parser) arguments() []*Argument {
+	if !p.is("(") {
+		return nil
+	}
+
+	p.next()
+
+	var args []*Argument
+
+	for !p.is(")") && p.err == nil {
+		a := &Argument{Name: p.name()}
+		p.expect(":")
+		a.Value = p.value()
+
+		args = append(args, a)
+	}
+
+	p.expect(")")
+
+	return args
+}
+
+func (p *parser) directives() []*Directive {
+	var dirs []*Directive
+
+	for p.is("@") && p.err == nil {
+		p.next()
+		dirs = append(dirs, &Directive{Name: p.name(), Arguments: p.arguments()})
+	}
+
+	return dirs
+}
+
+func (p *parser) value() *Value {
+	t := p.next()
+
+	switch t.Kind {
+	case Int:
+		return &Value{Kind: IntValue, Text: t.Text}
+	case Float:
+		return &Value{Kind: FloatValue, Text: t.Text}
+	case String:
+		return &Value{Kind: StringValue, Text: t.Text}
+	case Name:
+		switch t.Text {
+		case "true", "false":
+			return &Value{Kind: BooleanValue, Text: t.Text}
+		case "null":
+			return &Value{Kind: NullValue, Text: t.Text}
+		}
+
+		return &Value{Kind: EnumValue, Text: t.Text}
+	case Punct:
+		switch t.Text {
+		case "$":
+			return &Value{Kind: VariableValue, Text: p.name()}
+		case "[":
+			v := &Value{Kind: ListValue}
+			for !p.is("]") && p.err == nil {
+				v.List = append(v.List, p.value())
+			}
+
+			p.expect("]")
+
+			return v
+		case "{":
+			v := &Value{Kind: ObjectValue}
+			for !p.is("}") && p.err == nil {
+				f := &ObjectField{Name: p.name()}
+				p.expect(":")
+				f.Value = p.value()
+
+				v.Fields = append(v.Fields, f)
+			}
+
+			p.expect("}")
+
+			return v
+		}
+	}
+
+	p.pos-- // Report the error at the unexpected token.
+	p.fail("unexpected %q", t.Text)
+
+	return &Value{Kind: NullValue}
+}
@@ -0,0 +1,205 @@
+package language
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Print returns the document as compact GraphQL text.
+func Print(d *Document) string {
+	var p printer
+
+	for _, op := range d.Operations {
+		p.operation(op)
+	}
+
+	for _, f := range d.Fragments {
+		p.word("fragment", f.Name, "on", f.TypeCondition)
+		p.directives(f.Directives)
+		p.selectionSet(f.SelectionSet)
+	}
+
+	return p.String()
+}
+
+// A printer writes tokens, separating them only where needed.
+type printer struct {
+	b    strings.Builder
+	prev Kind
+}
+
+func (p *printer) String() string {
+	return p.b.String()
+}
+
+func (p *printer) token(kind Kind, text string) {
+	// Adjacent names and numbers need a separator, punctuation does not.
+	if kind != Punct && p.prev != Punct && p.b.Len() > 0 {
+		p.b.WriteByte(' ')
+	}
+
+	if kind == String {
+		text = quote(text)
+	}
+
+	p.b.WriteString(text)
+	p.prev = kind
+}
+
+func (p *printer) punct(text string) {
+	p.token(Punct, text)
+}
+
+func (p *printer) word(words ...string) {
+	for _, w := range words {
+		p.token(Name, w)
+	}
+}
+
+func (p *printer) operation(op *Operation) {
+	p.word(op.Type)
+
+	if op.Name != "" {
+		p.word(op.Name)
+	}
+
+	if len(op.Variables) > 0 {
+		p.punct("(")
+
+		for _, v := range op.Variables {
+			p.punct("$")
+			p.word(v.Name)
+			p.punct(":")
+			p.typeRef(v.Type)
+
+			if v.Default != nil {
+				p.punct("=")
+				p.value(v.Default)
+			}
+
+			p.directives(v.Directives)
+		}
+
+		p.punct(")")
+	}
+
+	p.directives(op.Directives)
+	p.selectionSet(op.SelectionSet)
+}
+
+// typeRef writes a type reference such as "[ID!]!".
+func (p *printer) typeRef(t string) {
+	name := strings.Trim(t, "[]!")
+	i := strings.Index(t, name)
+
+	for _, c := range t[:i] {
+		p.punct(string(c))
+	}
+
+	p.word(name)
+
+	for _, c := range t[i+len(name):] {
+		p.punct(string(c))
+	}
+}
+
+func (p *printer) selectionSet(sels []Selection) {
+	p.punct("{")
+
+	for _, s := range sels {
+		switch s := s.(type) {
+		case *Field:
+			if s.Alias != "" {
+				p.word(s.Alias)
+				p.punct(":")
+			}
+
+			p.word(s.Name)
+			p.arguments(s.Arguments)
+			p.directives(s.Directives)
+
+			if len(s.SelectionSet) > 0 {
+				p.selectionSet(s.SelectionSet)
+			}
+		case *FragmentSpread:
+			p.punct("...")
+			p.word(s.Name)
+			p.directives(s.Directives)
+		case *InlineFragment:
+			p.punct("...")
+
+			if s.TypeCondition != "" {
+				p.word("on", s.TypeCondition)
+			}
+
+			p.directives(s.Directives)
+			p.selectionSet(s.SelectionSet)
+		}
+	}
+
+	p.punct("}")
+}
+
+func (p *printer) directives(dirs []*Directive) {
+	for _, d := range dirs {
+		p.punct("@")
+		p.word(d.Name)
+		p.arguments(d.Arguments)
+	}
+}
+
+func (p *printer) arguments(args []*Argument) {
+	if len(args) == 0 {
+		return
+	}
+
+	p.punct("(")
+
+	for _, a := range args {
+		p.word(a.Name)
+		p.punct(":")
+		p.value(a.Value)
+	}
+
+	p.punct(")")
+}
+
+func (p *printer) value(v *Value) {
+	switch v.Kind {
+	case VariableValue:
+		p.punct("$")
+		p.word(v.Text)
+	case IntValue:
+		p.token(Int, v.Text)
+	case FloatValue:
+		p.token(Float, v.Text)
+	case StringValue:
+		p.token(String, v.Text)
+	case ListValue:
+		p.punct("[")
+
+		for _, item := range v.List {
+			p.value(item)
+		}
+
+		p.punct("]")
+	case ObjectValue:
+		p.punct("{")
+
+		for _, f := range v.Fields {
+			p.word(f.Name)
+			p.punct(":")
+			p.value(f.Value)
+		}
+
+		p.punct("}")
+	default:
+		p.word(v.Text)
+	}
+}
+
+// quote returns a GraphQL string literal. JSON string escapes are all valid in GraphQL.
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
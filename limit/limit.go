@@ -0,0 +1,226 @@
+// Package limit bounds the number of concurrent calls made to upstream services.
+//
+// A Limiter enforces three limits at once: a global limit, a limit per upstream host and a limit
+// per incoming request. Callers which cannot proceed wait in a queue. Waiting calls are granted
+// round-robin across requests rather than in arrival order, so a request which fans out to hundreds
+// of upstream calls cannot starve requests which only need a few.
+package limit
+
+import (
+	"context"
+	"sync"
+)
+
+// Config holds the limits. A limit of zero or less is unlimited.
+type Config struct {
+	Global     int // The maximum number of concurrent calls.
+	PerHost    int // The maximum number of concurrent calls to a single host.
+	PerRequest int // The maximum number of concurrent calls made on behalf of a single request.
+}
+
+// A Limiter bounds the concurrency of upstream calls. It is safe for concurrent use.
+type Limiter struct {
+	cfg Config
+
+	mu       sync.Mutex
+	active   int
+	hosts    map[string]int
+	requests map[*token]*request
+	waiting  []*request // Requests with queued waiters, in round-robin order.
+	queued   int
+}
+
+// New creates a Limiter.
+func New(cfg Config) *Limiter {
+	return &Limiter{cfg: cfg, hosts: make(map[string]int), requests: make(map[*token]*request)}
+}
+
+// The key type is unexported so the request does not collide with context values set by other
+// packages.
+type key struct{}
+
+// A token identifies an incoming request. It is not empty, so every token has a distinct address.
+type token struct{ _ byte }
+
+// request tracks the calls a Limiter has made on behalf of a single incoming request.
+type request struct {
+	token  *token
+	active int
+	queue  []*waiter
+}
+
+type waiter struct {
+	req     *request
+	host    string
+	granted chan struct{}
+}
+
+// Attach marks the context as belonging to a new incoming request.
+// Calls made with the context, or any context derived from it, count towards the request's limit
+// and share its place in the round-robin queue.
+func Attach(ctx context.Context) context.Context {
+	return context.WithValue(ctx, key{}, &token{})
+}
+
+// Acquire waits until a call to the host may proceed, or until the context is done.
+// The returned function must be called once the call has finished.
+//
+// Calls made with a context which was not passed through Attach are each treated as a request
+// of their own.
+func (l *Limiter) Acquire(ctx context.Context, host string) (release func(), err error) {
+	t, ok := ctx.Value(key{}).(*token)
+	if !ok {
+		t = &token{}
+	}
+
+	l.mu.Lock()
+	req, ok := l.requests[t]
+	if !ok {
+		req = &request{token: t}
+		l.requests[t] = req
+	}
+
+	w := &waiter{req: req, host: host, granted: make(chan struct{})}
+	l.enqueue(w)
+	l.dispatch()
+	l.mu.Unlock()
+
+	var once sync.Once
+	release = func() { once.Do(func() { l.release(w) }) }
+
+	select {
+	case <-w.granted:
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	select {
+	case <-w.granted:
+		// The call was granted while the context was being cancelled; give the slot back.
+		l.releaseLocked(w)
+	default:
+		l.remove(w)
+		l.forget(w.req)
+	}
+
+	return nil, ctx.Err()
+}
+
+// QueueDepth returns the number of calls waiting for a slot.
+func (l *Limiter) QueueDepth() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.queued
+}
+
+// Active returns the number of calls in progress.
+func (l *Limiter) Active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.active
+}
+
+func (l *Limiter) release(w *waiter) {
+	l.mu.Lock()
+	l.releaseLocked(w)
+	l.mu.Unlock()
+}
+
+func (l *Limiter) releaseLocked(w *waiter) {
+	l.active--
+	w.req.active--
+
+	if l.hosts[w.host]--; l.hosts[w.host] <= 0 {
+		delete(l.hosts, w.host)
+	}
+
+	l.forget(w.req)
+	l.dispatch()
+}
+
+// forget stops tracking a request once it has no calls in progress or waiting.
+func (l *Limiter) forget(req *request) {
+	if req.active == 0 && len(req.queue) == 0 {
+		delete(l.requests, req.token)
+	}
+}
+
+func (l *Limiter) enqueue(w *waiter) {
+	if len(w.req.queue) == 0 {
+		l.waiting = append(l.waiting, w.req)
+	}
+
+	w.req.queue = append(w.req.queue, w)
+	l.queued++
+}
+
+func (l *Limiter) remove(w *waiter) {
+	q := w.req.queue
+	for i := range q {
+		if q[i] == w {
+			w.req.queue = append(q[:i:i], q[i+1:]...)
+			l.queued--
+
+			break
+		}
+	}
+
+	if len(w.req.queue) == 0 {
+		l.unwait(w.req)
+	}
+}
+
+func (l *Limiter) unwait(req *request) {
+	for i, r := range l.waiting {
+		if r == req {
+			l.waiting = append(l.waiting[:i:i], l.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// dispatch grants queued calls until no more can proceed.
+// Each pass offers one slot to the oldest waiter of every waiting request in turn, and a request
+// which is granted a slot moves to the back of the line.
+func (l *Limiter) dispatch() {
+	for granted := true; granted; {
+		granted = false
+
+		for i := 0; i < len(l.waiting); i++ {
+			if !below(l.cfg.Global, l.active) {
+				return
+			}
+
+			req := l.waiting[i]
+			w := req.queue[0]
+
+			if !below(l.cfg.PerRequest, req.active) || !below(l.cfg.PerHost, l.hosts[w.host]) {
+				continue
+			}
+
+			req.queue = req.queue[1:]
+			l.queued--
+			l.active++
+			l.hosts[w.host]++
+			req.active++
+			close(w.granted)
+
+			l.waiting = append(l.waiting[:i:i], l.waiting[i+1:]...)
+			if len(req.queue) > 0 {
+				l.waiting = append(l.waiting, req)
+			}
+
+			i--
+			granted = true
+		}
+	}
+}
+
+func below(limit, n int) bool {
+	return limit <= 0 || n < limit
+}
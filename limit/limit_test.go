@@ -0,0 +1,190 @@
+package limit_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/limit"
+)
+
+// acquireAsync starts acquiring a slot and returns a channel which receives the release function.
+func acquireAsync(ctx context.Context, l *limit.Limiter, host string) <-chan func() {
+	ch := make(chan func(), 1)
+
+	go func() {
+		release, err := l.Acquire(ctx, host)
+		if err == nil {
+			ch <- release
+		}
+	}()
+
+	return ch
+}
+
+func waitForQueue(t *testing.T, l *limit.Limiter, depth int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return l.QueueDepth() == depth }, time.Second, time.Millisecond)
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Global", func(t *testing.T) {
+		l := limit.New(limit.Config{Global: 2})
+
+		r1, err := l.Acquire(ctx, "a")
+		require.NoError(t, err)
+		r2, err := l.Acquire(ctx, "b")
+		require.NoError(t, err)
+
+		third := acquireAsync(ctx, l, "c")
+		waitForQueue(t, l, 1)
+		require.Equal(t, 2, l.Active())
+
+		r1()
+		r3 := <-third
+		require.Equal(t, 0, l.QueueDepth())
+
+		r2()
+		r3()
+		require.Equal(t, 0, l.Active())
+	})
+
+	t.Run("PerHost", func(t *testing.T) {
+		l := limit.New(limit.Config{PerHost: 1})
+
+		r1, err := l.Acquire(ctx, "swapi.dev")
+		require.NoError(t, err)
+
+		// Another host is not affected by the busy one.
+		r2, err := l.Acquire(ctx, "example.com")
+		require.NoError(t, err)
+
+		same := acquireAsync(ctx, l, "swapi.dev")
+		waitForQueue(t, l, 1)
+
+		r1()
+		(<-same)()
+		r2()
+	})
+
+	t.Run("PerRequest", func(t *testing.T) {
+		l := limit.New(limit.Config{PerRequest: 1})
+		req := limit.Attach(ctx)
+
+		r1, err := l.Acquire(req, "a")
+		require.NoError(t, err)
+
+		// Another request is not affected by the busy one.
+		r2, err := l.Acquire(limit.Attach(ctx), "a")
+		require.NoError(t, err)
+
+		same := acquireAsync(req, l, "a")
+		waitForQueue(t, l, 1)
+
+		r1()
+		(<-same)()
+		r2()
+	})
+
+	t.Run("Fairness", func(t *testing.T) {
+		l := limit.New(limit.Config{Global: 1})
+		heavy, light := limit.Attach(ctx), limit.Attach(ctx)
+
+		release, err := l.Acquire(heavy, "a")
+		require.NoError(t, err)
+
+		var (
+			mu    sync.Mutex
+			order []string
+			wg    sync.WaitGroup
+		)
+
+		acquire := func(ctx context.Context, name string) {
+			defer wg.Done()
+
+			r, err := l.Acquire(ctx, "a")
+			require.NoError(t, err)
+
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+
+			r()
+		}
+
+		wg.Add(4)
+
+		for i := 0; i < 3; i++ {
+			go acquire(heavy, "heavy")
+		}
+
+		waitForQueue(t, l, 3)
+
+		go acquire(light, "light")
+
+		waitForQueue(t, l, 4)
+		release()
+		wg.Wait()
+
+		// The light request is served after at most one more call of the heavy request.
+		require.Contains(t, order[:2], "light")
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		l := limit.New(limit.Config{Global: 1})
+
+		release, err := l.Acquire(ctx, "a")
+		require.NoError(t, err)
+
+		cctx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+
+		go func() {
+			_, err := l.Acquire(cctx, "a")
+			done <- err
+		}()
+
+		waitForQueue(t, l, 1)
+		cancel()
+
+		require.ErrorIs(t, <-done, context.Canceled)
+		require.Equal(t, 0, l.QueueDepth())
+
+		release()
+		require.Equal(t, 0, l.Active())
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestTransport(t *testing.T) {
+	l := limit.New(limit.Config{Global: 1})
+
+	var inFlight int64
+
+	c := &http.Client{Transport: l.Transport(roundTripper(func(r *http.Request) (*http.Response, error) {
+		require.EqualValues(t, 1, atomic.AddInt64(&inFlight, 1))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}))}
+
+	resp, err := c.Get("https://swapi.dev/api/films/")
+	require.NoError(t, err)
+
+	// The slot is held until the body is closed.
+	require.Equal(t, 1, l.Active())
+
+	atomic.AddInt64(&inFlight, -1)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, 0, l.Active())
+}
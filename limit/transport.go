@@ -0,0 +1,54 @@
+package limit
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Transport returns an http.RoundTripper which acquires a slot from the Limiter for every request
+// sent through the base RoundTripper. The slot is held until the response body is closed, since
+// the connection stays busy while the body is read.
+func (l *Limiter) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return transport{limiter: l, base: base}
+}
+
+type transport struct {
+	limiter *Limiter
+	base    http.RoundTripper
+}
+
+func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	release, err := t.limiter.Acquire(r.Context(), r.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// releasingBody releases the slot of a request once its response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
+}
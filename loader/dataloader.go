@@ -0,0 +1,158 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/graph-gophers/dataloader"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/tracing"
+)
+
+// Options configures the batching and caching behaviour of a Loader.
+// The zero value batches every load requested within the dataloader's default wait window, with no
+// limit on batch size, and caches values for the lifetime of the Loader.
+type Options struct {
+	// Wait is how long the loader waits for more keys before dispatching a batch.
+	// When zero, the dataloader default of 16ms is used.
+	Wait time.Duration
+	// MaxBatch is the maximum number of keys dispatched in one batch. When zero, batches are unbounded.
+	MaxBatch int
+	// NoCache disables caching, so every load of a key is sent to the batch function.
+	NoCache bool
+}
+
+// Result is the (value, error) pair result of loading a specific key.
+type Result[V any] struct {
+	Value V
+	Error error
+}
+
+// BatchFunc loads the values for a batch of keys.
+// It must return exactly one result per key, in the same order as the keys.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) []Result[V]
+
+// A Loader batches and caches loads of values of type V by keys of type K.
+//
+// Loader is a type-safe wrapper around dataloader.Loader. Keys and values only ever enter the
+// underlying loader through the typed methods, so callers never need to assert the types of the
+// values they load.
+type Loader[K comparable, V any] struct {
+	ldr *dataloader.Loader
+}
+
+// NewLoader creates a Loader which dispatches batches of keys to the batch function.
+func NewLoader[K comparable, V any](batch BatchFunc[K, V], opts Options) *Loader[K, V] {
+	return newLoader("", batch, opts)
+}
+
+// newLoader creates a Loader which records its loads and batches on the trace of the context
+// under the name, unless the name is empty.
+func newLoader[K comparable, V any](name string, batch BatchFunc[K, V], opts Options) *Loader[K, V] {
+	var dopts []dataloader.Option
+
+	if name != "" {
+		dopts = append(dopts, dataloader.WithTracer(tracer{name: name}))
+	}
+
+	if opts.Wait > 0 {
+		dopts = append(dopts, dataloader.WithWait(opts.Wait))
+	}
+
+	if opts.MaxBatch > 0 {
+		dopts = append(dopts, dataloader.WithBatchCapacity(opts.MaxBatch))
+	}
+
+	if opts.NoCache {
+		dopts = append(dopts, dataloader.WithCache(&dataloader.NoCache{}))
+	}
+
+	return &Loader[K, V]{ldr: dataloader.NewBatchedLoader(adapt(batch), dopts...)}
+}
+
+// Load loads the value for a key.
+func (l *Loader[K, V]) Load(ctx context.Context, k K) (V, error) {
+	data, err := l.ldr.Load(ctx, typedKey[K]{k})()
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+
+	return value[V](data)
+}
+
+// value asserts the type of a value returned by the underlying loader. Values only enter it through
+// the typed methods, so a value of another type means the loader is miswired, which must not pass
+// for a missing value. A nil value is the zero value.
+func value[V any](data interface{}) (V, error) {
+	v, ok := data.(V)
+	if !ok && data != nil {
+		return v, errors.WrongType(v, data)
+	}
+
+	return v, nil
+}
+
+// Prime adds a value to the cache for a key, unless the key is already cached.
+func (l *Loader[K, V]) Prime(ctx context.Context, k K, v V) {
+	l.ldr.Prime(ctx, typedKey[K]{k}, v)
+}
+
+// typedKey implements dataloader.Key for keys of any comparable type.
+type typedKey[K comparable] struct {
+	k K
+}
+
+func (k typedKey[K]) String() string { return fmt.Sprint(k.k) }
+
+func (k typedKey[K]) Raw() interface{} { return k.k }
+
+// adapt converts a typed batch function into an untyped dataloader.BatchFunc.
+// Every key reaching the underlying loader is a typedKey[K], so the assertion cannot fail.
+func adapt[K comparable, V any](batch BatchFunc[K, V]) dataloader.BatchFunc {
+	return func(ctx context.Context, dkeys dataloader.Keys) []*dataloader.Result {
+		keys := make([]K, len(dkeys))
+		for i, k := range dkeys {
+			keys[i] = k.Raw().(K)
+		}
+
+		typed := batch(ctx, keys)
+		results := make([]*dataloader.Result, len(dkeys))
+
+		for i := range results {
+			if i >= len(typed) {
+				results[i] = &dataloader.Result{Error: fmt.Errorf("batch function returned %d results for %d keys", len(typed), len(keys))}
+				continue
+			}
+
+			results[i] = &dataloader.Result{Data: typed[i].Value, Error: typed[i].Error}
+		}
+
+		return results
+	}
+}
+
+// tracer records loads and batches on the trace of the context, as tracing.Tracer records
+// resolvers.
+type tracer struct {
+	name string
+}
+
+// TraceLoad records a load. LoadMany loads every key with Load, so loads are only counted here.
+func (t tracer) TraceLoad(ctx context.Context, _ dataloader.Key) (context.Context, dataloader.TraceLoadFinishFunc) {
+	tracing.RecordLoad(ctx, t.name)
+	return ctx, func(dataloader.Thunk) {}
+}
+
+func (t tracer) TraceLoadMany(ctx context.Context, _ dataloader.Keys) (context.Context, dataloader.TraceLoadManyFinishFunc) {
+	return ctx, func(dataloader.ThunkMany) {}
+}
+
+// TraceBatch records a batch. The batch function is called with the returned context, so the
+// calls it makes are attributed to the batch.
+func (t tracer) TraceBatch(ctx context.Context, keys dataloader.Keys) (context.Context, dataloader.TraceBatchFinishFunc) {
+	ctx, finish := tracing.StartBatch(ctx, t.name, keys.Keys())
+	return ctx, func([]*dataloader.Result) { finish() }
+}
@@ -0,0 +1,149 @@
+package loader_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/loader"
+)
+
+// recorder is a batch function which records the batches it receives.
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *recorder) load(ctx context.Context, keys []int) []loader.Result[string] {
+	r.mu.Lock()
+	r.batches = append(r.batches, append([]int(nil), keys...))
+	r.mu.Unlock()
+
+	results := make([]loader.Result[string], len(keys))
+	for i, k := range keys {
+		if k < 0 {
+			results[i].Error = errors.New("negative key")
+			continue
+		}
+
+		results[i].Value = strconv.Itoa(k)
+	}
+
+	return results
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.batches)
+}
+
+// loadAll loads the keys concurrently, so they are dispatched together. The results are in the
+// same order as the keys.
+func loadAll[K comparable, V any](ctx context.Context, ldr *loader.Loader[K, V], keys []K) []loader.Result[V] {
+	results := make([]loader.Result[V], len(keys))
+
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func(i int, k K) {
+			defer wg.Done()
+			results[i].Value, results[i].Error = ldr.Load(ctx, k)
+		}(i, k)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Load", func(t *testing.T) {
+		rec := &recorder{}
+		ldr := loader.NewLoader(rec.load, loader.Options{Wait: time.Millisecond})
+
+		v, err := ldr.Load(ctx, 7)
+		require.NoError(t, err)
+		require.Equal(t, "7", v)
+
+		_, err = ldr.Load(ctx, -1)
+		require.EqualError(t, err, "negative key")
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		rec := &recorder{}
+		ldr := loader.NewLoader(rec.load, loader.Options{Wait: 10 * time.Millisecond})
+
+		results := loadAll(ctx, ldr, []int{3, -1, 1})
+		require.Equal(t, "3", results[0].Value)
+		require.Error(t, results[1].Error)
+		require.Equal(t, "1", results[2].Value)
+		require.Equal(t, 1, rec.count())
+	})
+
+	t.Run("MaxBatch", func(t *testing.T) {
+		rec := &recorder{}
+		ldr := loader.NewLoader(rec.load, loader.Options{Wait: 10 * time.Millisecond, MaxBatch: 2})
+
+		for _, r := range loadAll(ctx, ldr, []int{1, 2, 3, 4, 5}) {
+			require.NoError(t, r.Error)
+		}
+
+		for _, b := range rec.batches {
+			require.LessOrEqual(t, len(b), 2)
+		}
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		rec := &recorder{}
+		ldr := loader.NewLoader(rec.load, loader.Options{Wait: time.Millisecond})
+
+		_, _ = ldr.Load(ctx, 1)
+		_, _ = ldr.Load(ctx, 1)
+		require.Equal(t, 1, rec.count())
+	})
+
+	t.Run("NoCache", func(t *testing.T) {
+		rec := &recorder{}
+		ldr := loader.NewLoader(rec.load, loader.Options{Wait: time.Millisecond, NoCache: true})
+
+		_, _ = ldr.Load(ctx, 1)
+		_, _ = ldr.Load(ctx, 1)
+		require.Equal(t, 2, rec.count())
+	})
+
+	t.Run("Prime", func(t *testing.T) {
+		rec := &recorder{}
+		ldr := loader.NewLoader(rec.load, loader.Options{Wait: time.Millisecond})
+
+		ldr.Prime(ctx, 1, "one")
+
+		v, err := ldr.Load(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "one", v)
+		require.Zero(t, rec.count())
+	})
+	t.Run("Miswired", func(t *testing.T) {
+		// A value of the wrong type is an error, rather than a missing value.
+		ldr := loader.NewMiswiredLoader[string](42)
+
+		_, err := ldr.Load(ctx, "a")
+		require.EqualError(t, err, "wrong type: wanted string, got int")
+
+		results := loadAll(ctx, ldr, []string{"b", "c"})
+		require.EqualError(t, results[1].Error, "wrong type: wanted string, got int")
+
+		// A nil value is the zero value.
+		v, err := loader.NewMiswiredLoader[*string](nil).Load(ctx, "d")
+		require.NoError(t, err)
+		require.Nil(t, v)
+	})
+}
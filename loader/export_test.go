@@ -0,0 +1,22 @@
+package loader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// NewMiswiredLoader creates a Loader whose underlying loader returns the value for every key,
+// whatever its type, as a miswired batch function would.
+func NewMiswiredLoader[V any](v interface{}) *Loader[string, V] {
+	batch := func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		for i := range results {
+			results[i] = &dataloader.Result{Data: v}
+		}
+
+		return results
+	}
+
+	return &Loader[string, V]{ldr: dataloader.NewBatchedLoader(batch)}
+}
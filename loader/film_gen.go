@@ -0,0 +1,70 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// LoadFilm loads a film resource from the SWAPI API URL.
+func LoadFilm(ctx context.Context, url string) (swapi.Film, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return swapi.Film{}, err
+	}
+
+	return ldrs.film.Load(ctx, url)
+}
+
+// PrimeFilms primes the film loader with the resources in a search results page.
+func PrimeFilms(ctx context.Context, page swapi.FilmPage) error {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range page.Films {
+		ldrs.film.Prime(ctx, v.URL, v)
+	}
+
+	return nil
+}
+
+type filmGetter interface {
+	Film(ctx context.Context, url string) (swapi.Film, error)
+}
+
+// filmLoader contains the client required to load film resources.
+type filmLoader struct {
+	get filmGetter
+}
+
+func newFilmLoader(client filmGetter) BatchFunc[string, swapi.Film] {
+	return filmLoader{get: client}.loadBatch
+}
+
+func (ldr filmLoader) loadBatch(ctx context.Context, urls []string) []Result[swapi.Film] {
+	var (
+		n       = len(urls)
+		results = make([]Result[swapi.Film], n)
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(n)
+
+	for i, url := range urls {
+		go func(i int, url string) {
+			defer wg.Done()
+
+			data, err := ldr.get.Film(ctx, url)
+			results[i] = Result[swapi.Film]{Value: data, Error: err}
+		}(i, url)
+	}
+
+	wg.Wait()
+
+	return results
+}
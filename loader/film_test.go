@@ -7,10 +7,6 @@ func TestFilmLoader(t *testing.T) {
 		// TODO: implement.
 	})
 
-	t.Run("LoadFilms", func(t *testing.T) {
-		// TODO: implement.
-	})
-
 	t.Run("PrimeFilms", func(t *testing.T) {
 		// TODO: implement.
 	})
@@ -1,10 +1,12 @@
 package loader
 
+//go:generate go run ../cmd/swapigen -kind loader
+
 import (
 	"context"
 	"fmt"
 
-	"github.com/graph-gophers/dataloader"
+	"github.com/tonyghita/graphql-go-example/swapi"
 )
 
 // The key type is created so that these values do not collide with other keys that some other
@@ -14,13 +16,17 @@ import (
 // https://medium.com/@matryer/context-keys-in-go-5312346a868d
 type key string
 
+const loadersKey key = "loaders"
+
+// The names of the loaders, as used in Settings.
 const (
-	filmLoaderKey     key = "film"
-	personLoaderKey   key = "person"
-	planetLoaderKey   key = "planet"
-	speciesLoaderKey  key = "species"
-	starshipLoaderKey key = "starship"
-	vehicleLoaderKey  key = "vehicle"
+	filmLoaderKey               key = "film"
+	personLoaderKey             key = "person"
+	planetLoaderKey             key = "planet"
+	speciesLoaderKey            key = "species"
+	speciesByHomeworldLoaderKey key = "speciesByHomeworld"
+	starshipLoaderKey           key = "starship"
+	vehicleLoaderKey            key = "vehicle"
 )
 
 type Client interface {
@@ -28,53 +34,76 @@ type Client interface {
 	personGetter
 	planetGetter
 	speciesGetter
+	allSpeciesGetter
 	starshipGetter
 	vehicleGetter
 }
 
-// Initialize a lookup map of context keys to batch functions.
-//
-// When Attach is called on the Collection, the batch functions are used to create new dataloader
-// instances. The instances are attached to the request context at the provided keys.
-//
-// The keys are then used to extract the dataloader instances from the request context.
-func Initialize(client Client) Collection {
-	return Collection{
-		lookup: map[key]dataloader.BatchFunc{
-			filmLoaderKey:     newFilmLoader(client),
-			personLoaderKey:   newPersonLoader(client),
-			planetLoaderKey:   newPlanetLoader(client),
-			speciesLoaderKey:  newSpeciesLoader(client),
-			starshipLoaderKey: newStarshipLoader(client),
-			vehicleLoaderKey:  newVehicleLoader(client),
-		},
+// Settings configures the loaders created for each request.
+type Settings struct {
+	// Default applies to every loader without an entry in Loaders.
+	Default Options
+	// Loaders overrides Default for individual loaders, keyed by loader name such as "person".
+	Loaders map[string]Options
+}
+
+func (s Settings) options(k key) Options {
+	if opts, ok := s.Loaders[k.String()]; ok {
+		return opts
 	}
+
+	return s.Default
 }
 
-// Collection holds an internal lookup of initialized batch data load functions.
+// Initialize a Collection which creates the loaders for each request.
+//
+// When Attach is called on the Collection, a new set of loaders is created using the client and the
+// settings, and attached to the request context.
+func Initialize(client Client, settings Settings) Collection {
+	return Collection{client: client, settings: settings}
+}
+
+// Collection holds the dependencies required to create the loaders for a request.
 type Collection struct {
-	lookup map[key]dataloader.BatchFunc
+	client   Client
+	settings Settings
 }
 
-// Attach creates new instances of dataloader.Loader and attaches the instances on the request context.
-// We do this because the dataloader has an in-memory cache that is scoped to the request.
+// loaders holds the loader instances for a single request.
+type loaders struct {
+	film               *Loader[string, swapi.Film]
+	person             *Loader[string, swapi.Person]
+	planet             *Loader[string, swapi.Planet]
+	species            *Loader[string, swapi.Species]
+	speciesByHomeworld *Loader[string, []swapi.Species]
+	starship           *Loader[string, swapi.Starship]
+	vehicle            *Loader[string, swapi.Vehicle]
+}
+
+// Attach creates new Loader instances and attaches them to the request context.
+// We do this because each loader has an in-memory cache that is scoped to the request.
 func (c Collection) Attach(ctx context.Context) context.Context {
-	for k, batchFn := range c.lookup {
-		ctx = context.WithValue(ctx, k, dataloader.NewBatchedLoader(batchFn))
-	}
+	s := c.settings
 
-	return ctx
+	return context.WithValue(ctx, loadersKey, &loaders{
+		film:               newLoader(filmLoaderKey.String(), newFilmLoader(c.client), s.options(filmLoaderKey)),
+		person:             newLoader(personLoaderKey.String(), newPersonLoader(c.client), s.options(personLoaderKey)),
+		planet:             newLoader(planetLoaderKey.String(), newPlanetLoader(c.client), s.options(planetLoaderKey)),
+		species:            newLoader(speciesLoaderKey.String(), newSpeciesLoader(c.client), s.options(speciesLoaderKey)),
+		speciesByHomeworld: newLoader(speciesByHomeworldLoaderKey.String(), newSpeciesByHomeworldLoader(c.client), s.options(speciesByHomeworldLoaderKey)),
+		starship:           newLoader(starshipLoaderKey.String(), newStarshipLoader(c.client), s.options(starshipLoaderKey)),
+		vehicle:            newLoader(vehicleLoaderKey.String(), newVehicleLoader(c.client), s.options(vehicleLoaderKey)),
+	})
 }
 
-// extract is a helper function to make common get-value, assert-type, return-error-or-value
-// operations easier.
-func extract(ctx context.Context, k key) (*dataloader.Loader, error) {
-	ldr, ok := ctx.Value(k).(*dataloader.Loader)
+// extract is a helper function to retrieve the request's loaders from the context.
+func extract(ctx context.Context) (*loaders, error) {
+	ldrs, ok := ctx.Value(loadersKey).(*loaders)
 	if !ok {
-		return nil, fmt.Errorf("unable to find %s loader on the request context", k)
+		return nil, fmt.Errorf("unable to find %s on the request context", loadersKey)
 	}
 
-	return ldr, nil
+	return ldrs, nil
 }
 
 // Implements Stringer.
@@ -0,0 +1,70 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// LoadPerson loads a person resource from the SWAPI API URL.
+func LoadPerson(ctx context.Context, url string) (swapi.Person, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return swapi.Person{}, err
+	}
+
+	return ldrs.person.Load(ctx, url)
+}
+
+// PrimePeople primes the person loader with the resources in a search results page.
+func PrimePeople(ctx context.Context, page swapi.PersonPage) error {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range page.People {
+		ldrs.person.Prime(ctx, v.URL, v)
+	}
+
+	return nil
+}
+
+type personGetter interface {
+	Person(ctx context.Context, url string) (swapi.Person, error)
+}
+
+// personLoader contains the client required to load person resources.
+type personLoader struct {
+	get personGetter
+}
+
+func newPersonLoader(client personGetter) BatchFunc[string, swapi.Person] {
+	return personLoader{get: client}.loadBatch
+}
+
+func (ldr personLoader) loadBatch(ctx context.Context, urls []string) []Result[swapi.Person] {
+	var (
+		n       = len(urls)
+		results = make([]Result[swapi.Person], n)
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(n)
+
+	for i, url := range urls {
+		go func(i int, url string) {
+			defer wg.Done()
+
+			data, err := ldr.get.Person(ctx, url)
+			results[i] = Result[swapi.Person]{Value: data, Error: err}
+		}(i, url)
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -7,10 +7,6 @@ func TestPersonLoader(t *testing.T) {
 		// TODO: implement
 	})
 
-	t.Run("LoadPeople", func(t *testing.T) {
-		// TODO: implement
-	})
-
 	t.Run("PrimePeople", func(t *testing.T) {
 		// TODO: implement
 	})
@@ -0,0 +1,70 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// LoadPlanet loads a planet resource from the SWAPI API URL.
+func LoadPlanet(ctx context.Context, url string) (swapi.Planet, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return swapi.Planet{}, err
+	}
+
+	return ldrs.planet.Load(ctx, url)
+}
+
+// PrimePlanets primes the planet loader with the resources in a search results page.
+func PrimePlanets(ctx context.Context, page swapi.PlanetPage) error {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range page.Planets {
+		ldrs.planet.Prime(ctx, v.URL, v)
+	}
+
+	return nil
+}
+
+type planetGetter interface {
+	Planet(ctx context.Context, url string) (swapi.Planet, error)
+}
+
+// planetLoader contains the client required to load planet resources.
+type planetLoader struct {
+	get planetGetter
+}
+
+func newPlanetLoader(client planetGetter) BatchFunc[string, swapi.Planet] {
+	return planetLoader{get: client}.loadBatch
+}
+
+func (ldr planetLoader) loadBatch(ctx context.Context, urls []string) []Result[swapi.Planet] {
+	var (
+		n       = len(urls)
+		results = make([]Result[swapi.Planet], n)
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(n)
+
+	for i, url := range urls {
+		go func(i int, url string) {
+			defer wg.Done()
+
+			data, err := ldr.get.Planet(ctx, url)
+			results[i] = Result[swapi.Planet]{Value: data, Error: err}
+		}(i, url)
+	}
+
+	wg.Wait()
+
+	return results
+}
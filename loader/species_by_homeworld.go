@@ -0,0 +1,56 @@
+package loader
+
+import (
+	"context"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// LoadSpeciesByHomeworld loads the species which originate from the planet at the SWAPI API URL.
+//
+// SWAPI only links species to their homeworld, not planets to their species, so the first load in
+// a batch fetches every species and the batch is answered from that list.
+func LoadSpeciesByHomeworld(ctx context.Context, planetURL string) ([]swapi.Species, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ldrs.speciesByHomeworld.Load(ctx, planetURL)
+}
+
+type allSpeciesGetter interface {
+	AllSpecies(ctx context.Context) ([]swapi.Species, error)
+}
+
+type speciesByHomeworldLoader struct {
+	get allSpeciesGetter
+}
+
+func newSpeciesByHomeworldLoader(client allSpeciesGetter) BatchFunc[string, []swapi.Species] {
+	return speciesByHomeworldLoader{get: client}.loadBatch
+}
+
+func (ldr speciesByHomeworldLoader) loadBatch(ctx context.Context, urls []string) []Result[[]swapi.Species] {
+	results := make([]Result[[]swapi.Species], len(urls))
+
+	all, err := ldr.get.AllSpecies(ctx)
+	if err != nil {
+		for i := range results {
+			results[i].Error = err
+		}
+
+		return results
+	}
+
+	byHomeworld := make(map[string][]swapi.Species)
+	for _, s := range all {
+		byHomeworld[s.HomeworldURL] = append(byHomeworld[s.HomeworldURL], s)
+	}
+
+	for i, url := range urls {
+		results[i].Value = byHomeworld[url]
+	}
+
+	return results
+}
@@ -0,0 +1,70 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// LoadSpecies loads a species resource from the SWAPI API URL.
+func LoadSpecies(ctx context.Context, url string) (swapi.Species, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return swapi.Species{}, err
+	}
+
+	return ldrs.species.Load(ctx, url)
+}
+
+// PrimeManySpecies primes the species loader with the resources in a search results page.
+func PrimeManySpecies(ctx context.Context, page swapi.SpeciesPage) error {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range page.Species {
+		ldrs.species.Prime(ctx, v.URL, v)
+	}
+
+	return nil
+}
+
+type speciesGetter interface {
+	Species(ctx context.Context, url string) (swapi.Species, error)
+}
+
+// speciesLoader contains the client required to load species resources.
+type speciesLoader struct {
+	get speciesGetter
+}
+
+func newSpeciesLoader(client speciesGetter) BatchFunc[string, swapi.Species] {
+	return speciesLoader{get: client}.loadBatch
+}
+
+func (ldr speciesLoader) loadBatch(ctx context.Context, urls []string) []Result[swapi.Species] {
+	var (
+		n       = len(urls)
+		results = make([]Result[swapi.Species], n)
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(n)
+
+	for i, url := range urls {
+		go func(i int, url string) {
+			defer wg.Done()
+
+			data, err := ldr.get.Species(ctx, url)
+			results[i] = Result[swapi.Species]{Value: data, Error: err}
+		}(i, url)
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -7,10 +7,6 @@ func TestSpeciesLoader(t *testing.T) {
 		// TODO: implement.
 	})
 
-	t.Run("LoadManySpecies", func(t *testing.T) {
-		// TODO: implement.
-	})
-
 	t.Run("PrimeSpecies", func(t *testing.T) {
 		// TODO: implement.
 	})
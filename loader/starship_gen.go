@@ -0,0 +1,70 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// LoadStarship loads a starship resource from the SWAPI API URL.
+func LoadStarship(ctx context.Context, url string) (swapi.Starship, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return swapi.Starship{}, err
+	}
+
+	return ldrs.starship.Load(ctx, url)
+}
+
+// PrimeStarships primes the starship loader with the resources in a search results page.
+func PrimeStarships(ctx context.Context, page swapi.StarshipPage) error {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range page.Starships {
+		ldrs.starship.Prime(ctx, v.URL, v)
+	}
+
+	return nil
+}
+
+type starshipGetter interface {
+	Starship(ctx context.Context, url string) (swapi.Starship, error)
+}
+
+// starshipLoader contains the client required to load starship resources.
+type starshipLoader struct {
+	get starshipGetter
+}
+
+func newStarshipLoader(client starshipGetter) BatchFunc[string, swapi.Starship] {
+	return starshipLoader{get: client}.loadBatch
+}
+
+func (ldr starshipLoader) loadBatch(ctx context.Context, urls []string) []Result[swapi.Starship] {
+	var (
+		n       = len(urls)
+		results = make([]Result[swapi.Starship], n)
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(n)
+
+	for i, url := range urls {
+		go func(i int, url string) {
+			defer wg.Done()
+
+			data, err := ldr.get.Starship(ctx, url)
+			results[i] = Result[swapi.Starship]{Value: data, Error: err}
+		}(i, url)
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -7,10 +7,6 @@ func TestStarshipLoader(t *testing.T) {
 		// TODO: implement.
 	})
 
-	t.Run("LoadStarships", func(t *testing.T) {
-		// TODO: implement.
-	})
-
 	t.Run("PrimeStarships", func(t *testing.T) {
 		// TODO: implement.
 	})
@@ -0,0 +1,70 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// LoadVehicle loads a vehicle resource from the SWAPI API URL.
+func LoadVehicle(ctx context.Context, url string) (swapi.Vehicle, error) {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return swapi.Vehicle{}, err
+	}
+
+	return ldrs.vehicle.Load(ctx, url)
+}
+
+// PrimeVehicles primes the vehicle loader with the resources in a search results page.
+func PrimeVehicles(ctx context.Context, page swapi.VehiclePage) error {
+	ldrs, err := extract(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range page.Vehicles {
+		ldrs.vehicle.Prime(ctx, v.URL, v)
+	}
+
+	return nil
+}
+
+type vehicleGetter interface {
+	Vehicle(ctx context.Context, url string) (swapi.Vehicle, error)
+}
+
+// vehicleLoader contains the client required to load vehicle resources.
+type vehicleLoader struct {
+	get vehicleGetter
+}
+
+func newVehicleLoader(client vehicleGetter) BatchFunc[string, swapi.Vehicle] {
+	return vehicleLoader{get: client}.loadBatch
+}
+
+func (ldr vehicleLoader) loadBatch(ctx context.Context, urls []string) []Result[swapi.Vehicle] {
+	var (
+		n       = len(urls)
+		results = make([]Result[swapi.Vehicle], n)
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(n)
+
+	for i, url := range urls {
+		go func(i int, url string) {
+			defer wg.Done()
+
+			data, err := ldr.get.Vehicle(ctx, url)
+			results[i] = Result[swapi.Vehicle]{Value: data, Error: err}
+		}(i, url)
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -7,10 +7,6 @@ func TestVehicleLoader(t *testing.T) {
 
 	})
 
-	t.Run("LoadVehicles", func(t *testing.T) {
-
-	})
-
 	t.Run("PrimeVehicles", func(t *testing.T) {
 
 	})
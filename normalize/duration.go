@@ -0,0 +1,28 @@
+package normalize
+
+import "strings"
+
+// A Duration is the parsed form of a SWAPI duration string, such as "2 months".
+type Duration struct {
+	Number
+	// Unit is the singular, lower case unit of time the number is measured in, such as "month".
+	// It is empty when the string did not name a unit.
+	Unit string
+}
+
+// ParseDuration parses a SWAPI duration string.
+// The number is parsed like any other SWAPI number, and the text following it names the unit.
+func ParseDuration(s string) Duration {
+	n := Parse(s)
+	if !n.Known {
+		return Duration{Number: n}
+	}
+
+	fields := strings.Fields(n.Qualifier)
+	if len(fields) == 0 {
+		return Duration{Number: n}
+	}
+
+	n.Qualifier = strings.Join(fields[1:], " ")
+	return Duration{Number: n, Unit: strings.TrimSuffix(fields[0], "s")}
+}
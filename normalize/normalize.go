@@ -0,0 +1,183 @@
+// Package normalize parses the loosely formatted values returned by the SWAPI REST API into
+// typed values.
+//
+// SWAPI serializes every numeric attribute as a string, and those strings are not always numbers.
+// Values such as "unknown", "n/a", "none", "1,000,000", "30-165" and "1 standard" all appear in
+// the dataset. This package turns them into a Number which knows whether the value is present,
+// whether it is a range, and whether any qualifying text was discarded along the way.
+package normalize
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// A Number is the parsed form of a SWAPI numeric string.
+type Number struct {
+	// Raw is the original string value returned by SWAPI.
+	Raw string
+	// Known is false when SWAPI does not have a value, e.g. "unknown" or "n/a".
+	Known bool
+	// Min and Max are the bounds of the value. They are equal unless the value is a range.
+	Min, Max float64
+	// Qualifier holds any trailing text that is not part of the number or a recognized unit,
+	// such as "(surface), 1 standard (Cloud City)".
+	Qualifier string
+
+	// digits is the lower bound as written, without thousands separators.
+	// Integer conversions use it so values beyond the precision of a float64 survive.
+	digits string
+}
+
+// unknowns are the values SWAPI uses to represent a missing value.
+var unknowns = map[string]bool{
+	"":           true,
+	"unknown":    true,
+	"n/a":        true,
+	"na":         true,
+	"none":       true,
+	"indefinite": true,
+}
+
+// units are unit suffixes that SWAPI appends to some values.
+// They carry no information beyond what the schema already documents, so they are dropped silently.
+var units = []string{"standard", "km", "kg", "cm", "m"}
+
+// Parse parses a SWAPI numeric string.
+// Commas used as thousands separators are removed, and a value of the form "a-b" is a range.
+func Parse(s string) Number {
+	n := Number{Raw: s}
+
+	v := strings.ToLower(strings.TrimSpace(s))
+	if unknowns[v] {
+		return n
+	}
+
+	v = strings.ReplaceAll(v, ",", "")
+
+	min, digits, rest, ok := leadingFloat(v)
+	if !ok {
+		return n
+	}
+
+	max := min
+	if r := strings.TrimSpace(rest); strings.HasPrefix(r, "-") {
+		if m, d, tail, ok := leadingFloat(strings.TrimSpace(r[1:])); ok {
+			max, rest = m, tail
+
+			if max < min {
+				min, max, digits = max, min, d
+			}
+		}
+	}
+
+	n.Known, n.Min, n.Max = true, min, max
+	n.Qualifier = qualifier(rest)
+	n.digits = digits
+
+	return n
+}
+
+// IsRange reports whether the value is a range of numbers rather than a single number.
+func (n Number) IsRange() bool {
+	return n.Known && n.Min != n.Max
+}
+
+// Exact reports whether the value was a single number without any qualifying text.
+func (n Number) Exact() bool {
+	return n.Known && !n.IsRange() && n.Qualifier == ""
+}
+
+// Float returns the value as a float, or nil when the value is not known.
+// A range resolves to its lower bound.
+func (n Number) Float() *float64 {
+	if !n.Known {
+		return nil
+	}
+
+	f := n.Min
+	return &f
+}
+
+// Int32 returns the value as a 32-bit integer, or nil when the value is not known, is not a whole
+// number, or does not fit in 32 bits.
+// A range resolves to its lower bound.
+func (n Number) Int32() *int32 {
+	if !n.Known || !fitsInt32(n.Min) {
+		return nil
+	}
+
+	i := int32(n.Min)
+	return &i
+}
+
+// Int64 returns the value as a 64-bit integer, or nil when the value is not known, is not a whole
+// number, or does not fit in 64 bits.
+// A range resolves to its lower bound.
+func (n Number) Int64() *int64 {
+	if !n.Known {
+		return nil
+	}
+
+	i, err := strconv.ParseInt(n.digits, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &i
+}
+
+// BigInt returns the value as an arbitrary-precision integer, or nil when the value is not known
+// or is not a whole number.
+// A range resolves to its lower bound.
+func (n Number) BigInt() *big.Int {
+	if !n.Known {
+		return nil
+	}
+
+	i, ok := new(big.Int).SetString(n.digits, 10)
+	if !ok {
+		return nil
+	}
+
+	return i
+}
+
+func fitsInt32(f float64) bool {
+	return f == math.Trunc(f) && f >= math.MinInt32 && f <= math.MaxInt32
+}
+
+// leadingFloat parses the number at the beginning of s.
+// It returns the number, the text it was parsed from and the unparsed remainder.
+func leadingFloat(s string) (float64, string, string, bool) {
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.') {
+		end++
+	}
+
+	if end == 0 {
+		return 0, "", s, false
+	}
+
+	f, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0, "", s, false
+	}
+
+	return f, s[:end], s[end:], true
+}
+
+// qualifier returns the trailing text of a value once any recognized unit suffix is removed.
+func qualifier(rest string) string {
+	rest = strings.TrimSpace(rest)
+
+	for _, u := range units {
+		if rest == u {
+			return ""
+		}
+	}
+
+	return rest
+}
@@ -0,0 +1,131 @@
+package normalize_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/normalize"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		input     string
+		known     bool
+		min, max  float64
+		qualifier string
+	}{
+		{"172", true, 172, 172, ""},
+		{"1,358", true, 1358, 1358, ""},
+		{"1000000000000", true, 1e12, 1e12, ""},
+		{"0.0001", true, 0.0001, 0.0001, ""},
+		{"30-165", true, 30, 165, ""},
+		{"5 - 6", true, 5, 6, ""},
+		{"1000km", true, 1000, 1000, ""},
+		{"1 standard", true, 1, 1, ""},
+		{"1.5 (surface), 1 standard (Cloud City)", true, 1.5, 1.5, "(surface) 1 standard (cloud city)"},
+		{"unknown", false, 0, 0, ""},
+		{"n/a", false, 0, 0, ""},
+		{"N/A", false, 0, 0, ""},
+		{"none", false, 0, 0, ""},
+		{"indefinite", false, 0, 0, ""},
+		{"", false, 0, 0, ""},
+		{"foo", false, 0, 0, ""},
+	}
+
+	for _, c := range cases {
+		n := normalize.Parse(c.input)
+
+		require.Equal(t, c.input, n.Raw)
+		require.Equal(t, c.known, n.Known, "Parse(%q).Known", c.input)
+		require.Equal(t, c.min, n.Min, "Parse(%q).Min", c.input)
+		require.Equal(t, c.max, n.Max, "Parse(%q).Max", c.input)
+		require.Equal(t, c.qualifier, n.Qualifier, "Parse(%q).Qualifier", c.input)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	t.Run("Float", func(t *testing.T) {
+		require.Nil(t, normalize.Parse("unknown").Float())
+		require.Equal(t, 30.0, *normalize.Parse("30-165").Float())
+	})
+
+	t.Run("Int32", func(t *testing.T) {
+		require.Nil(t, normalize.Parse("n/a").Int32())
+		require.Nil(t, normalize.Parse("1000000000000").Int32())
+		require.Nil(t, normalize.Parse("0.5").Int32())
+		require.Equal(t, int32(1855), *normalize.Parse("1,855").Int32())
+	})
+
+	t.Run("Int64", func(t *testing.T) {
+		require.Nil(t, normalize.Parse("unknown").Int64())
+		require.Nil(t, normalize.Parse("0.5").Int64())
+		require.Equal(t, int64(1000000000000), *normalize.Parse("1000000000000").Int64())
+		require.Equal(t, int64(30), *normalize.Parse("30-165").Int64())
+	})
+
+	t.Run("BigInt", func(t *testing.T) {
+		require.Nil(t, normalize.Parse("unknown").BigInt())
+		require.Equal(t, "100000000000000000000", normalize.Parse("100,000,000,000,000,000,000").BigInt().String())
+	})
+
+	t.Run("Exact", func(t *testing.T) {
+		require.True(t, normalize.Parse("1,855").Exact())
+		require.False(t, normalize.Parse("30-165").Exact())
+		require.False(t, normalize.Parse("unknown").Exact())
+	})
+}
+
+func TestWarn(t *testing.T) {
+	ctx, report := normalize.Attach(context.Background())
+	f := normalize.Field{Type: "Planet", ID: "1", Name: "population"}
+
+	require.Equal(t, int32(200000), *normalize.Int32(ctx, f, "200000"))
+	require.Empty(t, report.Warnings())
+
+	require.Nil(t, normalize.Int32(ctx, f, "unknown"))
+	require.Nil(t, normalize.Int32(ctx, f, "1000000000000"))
+
+	// The same warning is only recorded once.
+	require.Nil(t, normalize.BigInt(ctx, f, "unknown"))
+
+	warnings := report.Warnings()
+	require.Len(t, warnings, 2)
+	require.Equal(t, normalize.Warning{
+		Type:    "Planet",
+		ID:      "1",
+		Field:   "population",
+		Value:   "unknown",
+		Message: "value is unknown",
+	}, warnings[0])
+
+	// Warnings are dropped when no report is attached to the context.
+	require.Nil(t, normalize.Float(context.Background(), f, "unknown"))
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		input string
+		known bool
+		value float64
+		unit  string
+	}{
+		{"2 months", true, 2, "month"},
+		{"1 year", true, 1, "year"},
+		{"6 years", true, 6, "year"},
+		{"5 days", true, 5, "day"},
+		{"1-2 weeks", true, 1, "week"},
+		{"1", true, 1, ""},
+		{"unknown", false, 0, ""},
+		{"live food tanks", false, 0, ""},
+	}
+
+	for _, c := range cases {
+		d := normalize.ParseDuration(c.input)
+
+		require.Equal(t, c.known, d.Known, "ParseDuration(%q).Known", c.input)
+		require.Equal(t, c.value, d.Min, "ParseDuration(%q).Min", c.input)
+		require.Equal(t, c.unit, d.Unit, "ParseDuration(%q).Unit", c.input)
+	}
+}
@@ -0,0 +1,136 @@
+package normalize
+
+import (
+	"context"
+	"math/big"
+	"sync"
+)
+
+// The key type is unexported so the report does not collide with context values set by other
+// packages.
+type key struct{}
+
+// A Field identifies the resource attribute that a value was read from.
+type Field struct {
+	Type string // The GraphQL type name, such as "Planet".
+	ID   string // The identifier of the resource.
+	Name string // The GraphQL field name, such as "population".
+}
+
+// A Warning describes a value that could not be represented exactly in the response.
+type Warning struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+// A Report collects the data-quality warnings raised while resolving a single operation.
+// It is safe for concurrent use by multiple resolvers.
+type Report struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// Attach places a new, empty Report on the context.
+func Attach(ctx context.Context) (context.Context, *Report) {
+	r := &Report{}
+	return context.WithValue(ctx, key{}, r), r
+}
+
+// Warnings returns the warnings recorded so far.
+func (r *Report) Warnings() []Warning {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Warning(nil), r.warnings...)
+}
+
+// add records a warning, unless the same warning has been recorded already, as it is when a
+// resource is resolved more than once.
+func (r *Report) add(w Warning) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, recorded := range r.warnings {
+		if recorded == w {
+			return
+		}
+	}
+
+	r.warnings = append(r.warnings, w)
+}
+
+// Warn records a warning on the Report attached to the context.
+// It does nothing when no Report is attached.
+func Warn(ctx context.Context, f Field, value, message string) {
+	r, ok := ctx.Value(key{}).(*Report)
+	if !ok {
+		return
+	}
+
+	r.add(Warning{Type: f.Type, ID: f.ID, Field: f.Name, Value: value, Message: message})
+}
+
+// Float parses a SWAPI numeric string and returns it as a float.
+// A warning is recorded on the context whenever the value is not exact.
+func Float(ctx context.Context, f Field, raw string) *float64 {
+	n := Parse(raw)
+	Check(ctx, f, n)
+	return n.Float()
+}
+
+// Int32 parses a SWAPI numeric string and returns it as a 32-bit integer.
+// A warning is recorded on the context whenever the value is not exact or does not fit.
+func Int32(ctx context.Context, f Field, raw string) *int32 {
+	n := Parse(raw)
+	Check(ctx, f, n)
+
+	i := n.Int32()
+	if i == nil && n.Known {
+		Warn(ctx, f, raw, "value is not a 32-bit integer")
+	}
+
+	return i
+}
+
+// Int64 parses a SWAPI numeric string and returns it as a 64-bit integer.
+// A warning is recorded on the context whenever the value is not exact or does not fit.
+func Int64(ctx context.Context, f Field, raw string) *int64 {
+	n := Parse(raw)
+	Check(ctx, f, n)
+
+	i := n.Int64()
+	if i == nil && n.Known {
+		Warn(ctx, f, raw, "value is not a 64-bit integer")
+	}
+
+	return i
+}
+
+// BigInt parses a SWAPI numeric string and returns it as an arbitrary-precision integer.
+// A warning is recorded on the context whenever the value is not exact or not a whole number.
+func BigInt(ctx context.Context, f Field, raw string) *big.Int {
+	n := Parse(raw)
+	Check(ctx, f, n)
+
+	i := n.BigInt()
+	if i == nil && n.Known {
+		Warn(ctx, f, raw, "value is not an integer")
+	}
+
+	return i
+}
+
+// Check records a warning on the context when the parsed number is not exact.
+func Check(ctx context.Context, f Field, n Number) {
+	switch {
+	case !n.Known:
+		Warn(ctx, f, n.Raw, "value is unknown")
+	case n.IsRange():
+		Warn(ctx, f, n.Raw, "value is a range; resolved to the lower bound")
+	case n.Qualifier != "":
+		Warn(ctx, f, n.Raw, "qualifying text ignored: "+n.Qualifier)
+	}
+}
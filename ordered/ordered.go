@@ -0,0 +1,166 @@
+// Package ordered decodes JSON objects which keep the order of their members.
+//
+// graphql-go encodes response data with fields in the order the query selected them. Responses
+// which are rewritten after execution are decoded with this package, so they keep that order.
+package ordered
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// An Object is a decoded JSON object which keeps the order of its members, so re-encoded
+// responses list fields in the order the query selected them.
+type Object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// Get returns the value of the member with the key.
+func (o *Object) Get(key string) (interface{}, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// Keys returns the keys of the members, in order.
+func (o *Object) Keys() []string {
+	return o.keys
+}
+
+// Set sets the value of the member with the key. A new member is added after the existing ones.
+func (o *Object) Set(key string, v interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+
+	o.values[key] = v
+}
+
+// Remove removes the member with the key.
+func (o *Object) Remove(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+
+	delete(o.values, key)
+
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// MarshalJSON encodes the members in order.
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Decode decodes JSON into Objects, []interface{} slices and scalars. Numbers are kept as
+// json.Number so they are re-encoded exactly.
+func Decode(data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		o := &Object{values: map[string]interface{}{}}
+
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key %v", k)
+			}
+
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			o.Set(key, v)
+		}
+
+		_, err = dec.Token() // Closing brace.
+
+		return o, err
+	case json.Delim('['):
+		l := []interface{}{}
+
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			l = append(l, v)
+		}
+
+		_, err = dec.Token() // Closing bracket.
+
+		return l, err
+	}
+
+	return t, nil
+}
+
+// RemoveAll removes the key from every object within v.
+func RemoveAll(v interface{}, key string) {
+	switch v := v.(type) {
+	case *Object:
+		v.Remove(key)
+
+		for _, child := range v.values {
+			RemoveAll(child, key)
+		}
+	case []interface{}:
+		for _, item := range v {
+			RemoveAll(item, key)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/limit"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/ordered"
+	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+const queryUsage = `usage: graphql-go-example query [flags] [query]
+
+Execute a query against this API without starting the server, and print its data. The query is
+read from the argument, from the file given with -file, or else from stdin. Errors and data-quality
+warnings are printed on stderr; the exit status is 1 when the query has errors.
+
+Flags:
+`
+
+// queryUpstreamLimits bound the concurrent calls the query subcommand makes to SWAPI, as the
+// server bounds those of a single request.
+var queryUpstreamLimits = limit.Config{PerRequest: 8}
+
+// queryCommand runs the query subcommand and returns the exit status. The directory given with
+// -dataset is opened in fsys, the file system rooted at /.
+func queryCommand(args []string, fsys fs.FS, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, queryUsage)
+		fs.PrintDefaults()
+	}
+
+	var (
+		file      = fs.String("file", "", "read the query from `path`; - reads stdin")
+		variables = fs.String("variables", "", "the query's variables, as a JSON `object`")
+		operation = fs.String("operation", "", "the `name` of the operation to execute, when the query has several")
+		format    = fs.String("format", "json", "print the data in `format` json, or table or csv when it is a flat list")
+		dataset   = fs.String("dataset", "", "serve SWAPI from the offline dataset in `dir` instead of https://swapi.dev")
+		roles     = fs.String("roles", "", "execute as a principal granted the comma-separated `roles`, rather than anonymously")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return 2 // The flag set has printed the usage.
+	}
+
+	if fs.NArg() > 1 || (fs.NArg() == 1 && *file != "") {
+		fs.Usage()
+		return 2
+	}
+
+	switch *format {
+	case "json", "table", "csv":
+	default:
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return 2
+	}
+
+	query, err := readQuery(fs.Arg(0), *file, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "reading query: %s\n", err)
+		return 2
+	}
+
+	var vars map[string]interface{}
+	if *variables != "" {
+		if err := json.Unmarshal([]byte(*variables), &vars); err != nil {
+			fmt.Fprintf(stderr, "reading variables: %s\n", err)
+			return 2
+		}
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if *dataset != "" {
+		if transport, err = loadDataset(fsys, *dataset); err != nil {
+			fmt.Fprintf(stderr, "reading dataset %s: %s\n", *dataset, err)
+			return 2
+		}
+	}
+
+	c := swapi.NewClient(&http.Client{Transport: upstreamTransport(limit.New(queryUpstreamLimits), transport)})
+
+	sdl, err := schema.String()
+	if err != nil {
+		fmt.Fprintf(stderr, "reading embedded schema contents: %s\n", err)
+		return 2
+	}
+
+	exec, err := newSchema(sdl, c)
+	if err != nil {
+		fmt.Fprintf(stderr, "creating schema: %s\n", err)
+		return 2
+	}
+
+	rules, err := auth.NewRules(sdl)
+	if err != nil {
+		fmt.Fprintf(stderr, "reading authorization rules: %s\n", err)
+		return 2
+	}
+
+	var principal *auth.Principal
+	if *roles != "" {
+		principal = &auth.Principal{Subject: "query", Roles: strings.Split(*roles, ","), Method: "cli"}
+	}
+
+	// Prepare the context as the server prepares the context of a request.
+	ctx := auth.Attach(context.Background(), principal)
+	ctx = rules.Attach(ctx)
+	ctx = loader.Initialize(c, loaderSettings).Attach(ctx)
+	ctx = limit.Attach(ctx)
+	ctx, report := normalize.Attach(ctx)
+	ctx, collector := errors.Collect(ctx)
+
+	res := exec.Exec(ctx, query, *operation, vars)
+	res.Errors = errors.Expand(append(res.Errors, collector.Errors()...))
+
+	for _, w := range report.Warnings() {
+		fmt.Fprintf(stderr, "warning: %s %s %s %q: %s\n", w.Type, w.ID, w.Field, w.Value, w.Message)
+	}
+
+	for _, err := range res.Errors {
+		fmt.Fprintf(stderr, "error: %s\n", describe(err))
+	}
+
+	if len(res.Data) > 0 && string(res.Data) != "null" {
+		if err := printData(stdout, res.Data, *format); err != nil {
+			fmt.Fprintf(stderr, "printing data: %s\n", err)
+			return 2
+		}
+	}
+
+	if len(res.Errors) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// readQuery reads the query from the argument, the file, or stdin, in that order of preference.
+func readQuery(arg, file string, stdin io.Reader) (string, error) {
+	if arg != "" {
+		return arg, nil
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+
+	if file != "" && file != "-" {
+		b, err = os.ReadFile(file)
+	} else {
+		b, err = io.ReadAll(stdin)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(string(b)) == "" {
+		return "", fmt.Errorf("the query is empty")
+	}
+
+	return string(b), nil
+}
+
+// loadDataset loads the dataset in dir, a path relative to the working directory or absolute,
+// from fsys, the file system rooted at /.
+func loadDataset(fsys fs.FS, dir string) (*swapi.Dataset, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := fs.Sub(fsys, strings.TrimPrefix(filepath.ToSlash(dir), "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	return swapi.LoadDataset(sub)
+}
+
+// describe writes an error with its path, such as `resource not found (at films.0.title)`.
+func describe(err *gqlerrors.QueryError) string {
+	if len(err.Path) == 0 {
+		return err.Message
+	}
+
+	path := make([]string, len(err.Path))
+	for i, p := range err.Path {
+		path[i] = fmt.Sprint(p)
+	}
+
+	return fmt.Sprintf("%s (at %s)", err.Message, strings.Join(path, "."))
+}
+
+func printData(w io.Writer, data json.RawMessage, format string) error {
+	if format == "json" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return err
+		}
+
+		buf.WriteByte('\n')
+
+		_, err := buf.WriteTo(w)
+
+		return err
+	}
+
+	columns, rows, err := flatten(data)
+	if err != nil {
+		return fmt.Errorf("%w; use -format json", err)
+	}
+
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		_ = cw.Write(columns)
+		_ = cw.WriteAll(rows) // Flushes.
+
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// flatten turns data which is a flat list into the rows of a table: data with a single field,
+// which is a list of objects whose fields are scalars or lists of scalars, or a list of scalars.
+// The columns are the fields of the objects, in the order the query selected them.
+func flatten(data json.RawMessage) ([]string, [][]string, error) {
+	v, err := ordered.Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root, ok := v.(*ordered.Object)
+	if !ok || len(root.Keys()) != 1 {
+		return nil, nil, fmt.Errorf("a table needs a query with a single root field")
+	}
+
+	name := root.Keys()[0]
+	field, _ := root.Get(name)
+
+	items, ok := field.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a list", name)
+	}
+
+	var (
+		columns []string
+		indexes = map[string]int{}
+		cells   = make([]map[string]string, len(items))
+	)
+
+	column := func(key string) {
+		if _, ok := indexes[key]; !ok {
+			indexes[key] = len(columns)
+			columns = append(columns, key)
+		}
+	}
+
+	for i, item := range items {
+		cells[i] = map[string]string{}
+
+		obj, ok := item.(*ordered.Object)
+		if !ok {
+			s, ok := cell(item)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s is not a list of scalars or objects", name)
+			}
+
+			column(name)
+			cells[i][name] = s
+
+			continue
+		}
+
+		for _, key := range obj.Keys() {
+			v, _ := obj.Get(key)
+
+			s, ok := cell(v)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s.%s is not a scalar or a list of scalars", name, key)
+			}
+
+			column(key)
+			cells[i][key] = s
+		}
+	}
+
+	rows := make([][]string, len(cells))
+	for i, c := range cells {
+		rows[i] = make([]string, len(columns))
+		for key, s := range c {
+			rows[i][indexes[key]] = s
+		}
+	}
+
+	return columns, rows, nil
+}
+
+// cell writes a scalar, or a list of scalars separated by commas. Null is written as an empty
+// string.
+func cell(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			if _, ok := item.([]interface{}); ok {
+				return "", false
+			}
+
+			s, ok := cell(item)
+			if !ok {
+				return "", false
+			}
+
+			parts[i] = s
+		}
+
+		return strings.Join(parts, ", "), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var queryDataset = fstest.MapFS{
+	"data/swapi/films.json": {Data: []byte(`[
+		{"title": "A New Hope", "episode_id": 4, "url": "https://swapi.dev/api/films/1/",
+			"starships": ["https://swapi.dev/api/starships/9/"]},
+		{"title": "The Empire Strikes Back", "episode_id": 5, "url": "https://swapi.dev/api/films/2/"}
+	]`)},
+}
+
+// runQuery runs the query subcommand against the dataset, with stdin as its standard input.
+func runQuery(t *testing.T, stdin string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+
+	var out, errs bytes.Buffer
+	args = append([]string{"-dataset", "/data/swapi"}, args...)
+	code = queryCommand(args, queryDataset, strings.NewReader(stdin), &out, &errs)
+
+	return out.String(), errs.String(), code
+}
+
+func TestQueryCommand(t *testing.T) {
+	const films = `{ films { title episode } }`
+
+	t.Run("JSON", func(t *testing.T) {
+		stdout, stderr, code := runQuery(t, "", films)
+		require.Equal(t, 0, code)
+		require.Empty(t, stderr)
+		require.JSONEq(t, `{"films": [
+			{"title": "A New Hope", "episode": 4},
+			{"title": "The Empire Strikes Back", "episode": 5}
+		]}`, stdout)
+	})
+
+	t.Run("Table", func(t *testing.T) {
+		stdout, _, code := runQuery(t, "", "-format", "table", films)
+		require.Equal(t, 0, code)
+		require.Equal(t, "title                    episode\n"+
+			"A New Hope               4\n"+
+			"The Empire Strikes Back  5\n", stdout)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		stdout, _, code := runQuery(t, films, "-format", "csv", "-file", "-")
+		require.Equal(t, 0, code)
+		require.Equal(t, "title,episode\nA New Hope,4\nThe Empire Strikes Back,5\n", stdout)
+	})
+
+	t.Run("Variables", func(t *testing.T) {
+		stdout, _, code := runQuery(t, "", "-format", "csv", "-variables", `{"title": "empire"}`,
+			`query ($title: String) { films(title: $title) { title } }`)
+		require.Equal(t, 0, code)
+		require.Equal(t, "title\nThe Empire Strikes Back\n", stdout)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		// The film's starship is not in the dataset. The data is printed anyway.
+		stdout, stderr, code := runQuery(t, "", `{ films(title: "hope") { title starships { name } } }`)
+		require.Equal(t, 1, code)
+		require.JSONEq(t, `{"films": [{"title": "A New Hope", "starships": [null]}]}`, stdout)
+		require.Equal(t, "error: GET https://swapi.dev/api/starships/9/: 404 Not Found (at films.0.starships.0.name)\n", stderr)
+
+		stdout, stderr, code = runQuery(t, "", `{ films { nope } }`)
+		require.Equal(t, 1, code)
+		require.Empty(t, stdout)
+		require.Contains(t, stderr, `Cannot query field "nope" on type "Film"`)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-format", "xml", films},
+			{"-variables", "[1]", films},
+			{"-format", "table", `{ __typename }`},
+			{films, films},
+			{"-dataset", "/data/nope", films},
+		} {
+			_, stderr, code := runQuery(t, "", args...)
+			require.Equal(t, 2, code, "%q", args)
+			require.NotEmpty(t, stderr, "%q", args)
+		}
+
+		_, stderr, code := runQuery(t, " \n")
+		require.Equal(t, 2, code)
+		require.Equal(t, "reading query: the query is empty\n", stderr)
+	})
+}
@@ -0,0 +1,81 @@
+// Package ratelimit limits how much each client may ask of the API, so no single client can exhaust
+// the upstream SWAPI rate limit for everyone.
+//
+// Every client has two token buckets: one is spent by requests, the other by the estimated cost of
+// the operations they execute. Buckets start full and refill evenly over time. Their state is kept
+// in a Store, which is in memory by default.
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// A Limit allows Capacity tokens to be spent at once, and refills them evenly over Per.
+// A Limit with no capacity is unlimited.
+type Limit struct {
+	Capacity int
+	Per      time.Duration
+}
+
+// Unlimited reports whether the limit allows any number of tokens to be spent.
+func (l Limit) Unlimited() bool {
+	return l.Capacity <= 0 || l.Per <= 0
+}
+
+// rate returns the number of tokens refilled per second.
+func (l Limit) rate() float64 {
+	return float64(l.Capacity) / l.Per.Seconds()
+}
+
+// A Bucket holds the tokens left to a client under a limit.
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// A Decision reports whether tokens could be spent, and the state of the bucket afterwards.
+type Decision struct {
+	Allowed bool
+	Limit   Limit
+	// Remaining is the number of whole tokens left.
+	Remaining int
+	// Reset is how long the bucket takes to refill completely.
+	Reset time.Duration
+	// RetryAfter is how long to wait until the tokens can be spent, when they could not.
+	RetryAfter time.Duration
+}
+
+// Take spends n tokens from the bucket at the time now, when it holds enough of them, and returns
+// the bucket's new state. A zero Bucket is full. Nothing is spent when there are not enough tokens.
+func (l Limit) Take(b Bucket, n float64, now time.Time) (Bucket, Decision) {
+	if l.Unlimited() {
+		return b, Decision{Allowed: true, Limit: l}
+	}
+
+	capacity := float64(l.Capacity)
+
+	tokens := capacity
+	if !b.Updated.IsZero() {
+		tokens = math.Min(capacity, b.Tokens+now.Sub(b.Updated).Seconds()*l.rate())
+	}
+
+	d := Decision{Limit: l}
+
+	if n <= tokens {
+		tokens -= n
+		d.Allowed = true
+	} else {
+		d.RetryAfter = l.duration(n - tokens)
+	}
+
+	d.Remaining = int(math.Floor(tokens))
+	d.Reset = l.duration(capacity - tokens)
+
+	return Bucket{Tokens: tokens, Updated: now}, d
+}
+
+// duration returns how long the limit takes to refill n tokens.
+func (l Limit) duration(n float64) time.Duration {
+	return time.Duration(math.Ceil(n / l.rate() * float64(time.Second)))
+}
@@ -0,0 +1,188 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/introspection"
+
+	"github.com/tonyghita/graphql-go-example/language"
+)
+
+// DefaultListSize is the number of items lists are assumed to hold, which is the size of a page of
+// SWAPI results.
+const DefaultListSize = 10
+
+// Costs estimates the cost of operations before they execute, as the number of resources they may
+// load. Every field returning an object costs 1, and every field returning a list of objects costs
+// 1 per item. Lists are assumed to hold ListSize items, and the fields selected on their items are
+// counted once per item, so nested lists multiply:
+//
+//	{ films { title characters { name } } }  # 10 films + 10 × 10 characters = 110
+//
+// Scalar fields and introspection are free, since they load nothing from SWAPI.
+type Costs struct {
+	// ListSize is the number of items lists are assumed to hold. It defaults to DefaultListSize.
+	ListSize int
+	// SizedBy maps the coordinates of list fields which return an item per item of one of their
+	// list arguments, such as "Query._entities", to the name of that argument. Those lists are
+	// assumed to hold as many items as the argument.
+	SizedBy map[string]string
+
+	fields map[string]fieldType
+	query  string
+}
+
+// A fieldType describes the type a field returns.
+type fieldType struct {
+	// name is the name of the type, unwrapped from lists and non-null types.
+	name   string
+	object bool
+	list   bool
+}
+
+// NewCosts reads the types of the fields of the schema.
+func NewCosts(sdl string) (*Costs, error) {
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	if err != nil {
+		return nil, err
+	}
+
+	inspected := s.Inspect()
+	all := &struct{ IncludeDeprecated bool }{true}
+
+	c := &Costs{
+		// Federation's _entities returns an entity per representation.
+		SizedBy: map[string]string{"Query._entities": "representations"},
+		fields:  map[string]fieldType{},
+		query:   *inspected.QueryType().Name(),
+	}
+
+	for _, t := range inspected.Types() {
+		name := *t.Name()
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+
+		if fields := t.Fields(all); fields != nil {
+			for _, f := range *fields {
+				c.fields[name+"."+f.Name()] = typeOf(f.Type())
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func typeOf(t *introspection.Type) fieldType {
+	var ft fieldType
+
+	for t.Name() == nil {
+		if t.Kind() == "LIST" {
+			ft.list = true
+		}
+
+		t = t.OfType()
+	}
+
+	ft.name = *t.Name()
+
+	switch t.Kind() {
+	case "OBJECT", "INTERFACE", "UNION":
+		ft.object = true
+	}
+
+	return ft
+}
+
+// Estimate returns the cost of the operation, executed with the variables. Fields skipped with
+// @skip or @include are counted anyway, so the cost is never underestimated. Fields which are not
+// in the schema cost nothing, since the operation fails validation.
+func (c *Costs) Estimate(query, opName string, variables map[string]interface{}) (int, error) {
+	doc, err := language.Parse(query)
+	if err != nil {
+		return 0, err
+	}
+
+	op := doc.Operation(opName)
+	if op == nil {
+		return 0, fmt.Errorf("no operation named %q", opName)
+	}
+
+	e := &estimator{costs: c, doc: doc, vars: variables, spreading: map[string]bool{}}
+
+	return e.selections(c.query, op.SelectionSet), nil
+}
+
+func (c *Costs) listSize() int {
+	if c.ListSize <= 0 {
+		return DefaultListSize
+	}
+
+	return c.ListSize
+}
+
+type estimator struct {
+	costs *Costs
+	doc   *language.Document
+	vars  map[string]interface{}
+	// spreading holds the fragments being estimated, so a document which spreads a fragment
+	// within itself cannot recurse forever. Such documents fail validation.
+	spreading map[string]bool
+}
+
+func (e *estimator) selections(typ string, sels []language.Selection) int {
+	cost := 0
+
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *language.Field:
+			ft, ok := e.costs.fields[typ+"."+sel.Name]
+			if !ok || !ft.object {
+				continue
+			}
+
+			items := 1
+			if ft.list {
+				items = e.listSize(typ, sel)
+			}
+
+			cost += items * (1 + e.selections(ft.name, sel.SelectionSet))
+		case *language.InlineFragment:
+			cond := sel.TypeCondition
+			if cond == "" {
+				cond = typ
+			}
+
+			cost += e.selections(cond, sel.SelectionSet)
+		case *language.FragmentSpread:
+			f := e.doc.Fragment(sel.Name)
+			if f == nil || e.spreading[sel.Name] {
+				continue
+			}
+
+			e.spreading[sel.Name] = true
+			cost += e.selections(f.TypeCondition, f.SelectionSet)
+			delete(e.spreading, sel.Name)
+		}
+	}
+
+	return cost
+}
+
+// listSize returns the number of items the list field is assumed to hold.
+func (e *estimator) listSize(typ string, f *language.Field) int {
+	name, ok := e.costs.SizedBy[typ+"."+f.Name]
+	if !ok {
+		return e.costs.listSize()
+	}
+
+	if arg := f.Argument(name); arg != nil {
+		if items, ok := arg.Value.Resolve(e.vars).([]interface{}); ok {
+			return len(items)
+		}
+	}
+
+	return e.costs.listSize()
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+)
+
+// A Tier holds the limits of a class of clients.
+type Tier struct {
+	// Requests limits the number of requests. A batched request counts once.
+	Requests Limit
+	// Cost limits the total estimated cost of the operations executed.
+	Cost Limit
+}
+
+// A Limiter decides whether clients may make requests.
+type Limiter struct {
+	// Store keeps the buckets of every client.
+	Store Store
+	// Costs estimates the cost of operations.
+	Costs *Costs
+
+	// Anonymous applies to requests without a principal, which are limited by IP address.
+	Anonymous Tier
+	// Authenticated applies to principals with no role in Tiers.
+	Authenticated Tier
+	// Tiers maps roles to the tiers of the principals granted them, regardless of case. A
+	// principal with several such roles gets the tier of the first.
+	Tiers map[string]Tier
+}
+
+// Tier returns the tier of the principal. A nil principal is anonymous.
+func (l *Limiter) Tier(p *auth.Principal) Tier {
+	if p == nil {
+		return l.Anonymous
+	}
+
+	for _, role := range p.Roles {
+		for name, t := range l.Tiers {
+			if strings.EqualFold(name, role) {
+				return t
+			}
+		}
+	}
+
+	return l.Authenticated
+}
+
+// A Result holds the decisions of both of a client's buckets.
+type Result struct {
+	Requests Decision
+	Cost     Decision
+}
+
+// Allowed reports whether the request may proceed.
+func (r Result) Allowed() bool {
+	return r.Requests.Allowed && r.Cost.Allowed
+}
+
+// RetryAfter returns how long to wait before the request could be allowed.
+func (r Result) RetryAfter() time.Duration {
+	if r.Requests.RetryAfter > r.Cost.RetryAfter {
+		return r.Requests.RetryAfter
+	}
+
+	return r.Cost.RetryAfter
+}
+
+// Allow spends a request and the cost from the buckets of the client identified by key, under the
+// limits of the tier. When the request bucket is empty, the cost is not spent. A request rejected
+// for its cost still counts against the request limit, so clients cannot retry expensive operations
+// as fast as they like.
+func (l *Limiter) Allow(ctx context.Context, key string, t Tier, cost int, now time.Time) (Result, error) {
+	var (
+		res Result
+		err error
+	)
+
+	if res.Requests, err = l.Store.Take(ctx, "requests:"+key, t.Requests, 1, now); err != nil {
+		return res, err
+	}
+
+	if !res.Requests.Allowed {
+		res.Cost = Decision{Allowed: true, Limit: t.Cost}
+		return res, nil
+	}
+
+	res.Cost, err = l.Store.Take(ctx, "cost:"+key, t.Cost, float64(cost), now)
+
+	return res, err
+}
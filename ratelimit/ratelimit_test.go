@@ -0,0 +1,149 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/ratelimit"
+	"github.com/tonyghita/graphql-go-example/schema"
+)
+
+func TestLimit(t *testing.T) {
+	l := ratelimit.Limit{Capacity: 10, Per: 10 * time.Second} // One token per second.
+	now := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	b, d := l.Take(ratelimit.Bucket{}, 4, now)
+	require.True(t, d.Allowed)
+	require.Equal(t, 6, d.Remaining)
+	require.Equal(t, 4*time.Second, d.Reset)
+
+	// Nothing is spent when there are not enough tokens.
+	b, d = l.Take(b, 8, now)
+	require.False(t, d.Allowed)
+	require.Equal(t, 6, d.Remaining)
+	require.Equal(t, 2*time.Second, d.RetryAfter)
+
+	// Tokens refill over time, up to the capacity.
+	b, d = l.Take(b, 8, now.Add(2*time.Second))
+	require.True(t, d.Allowed)
+	require.Equal(t, 0, d.Remaining)
+
+	_, d = l.Take(b, 0, now.Add(time.Hour))
+	require.Equal(t, 10, d.Remaining)
+	require.Zero(t, d.Reset)
+
+	_, d = ratelimit.Limit{}.Take(ratelimit.Bucket{}, 1e9, now)
+	require.True(t, d.Allowed)
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := ratelimit.NewMemory()
+	l := ratelimit.Limit{Capacity: 2, Per: time.Second}
+	now := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	for _, allowed := range []bool{true, true, false} {
+		d, err := m.Take(ctx, "a", l, 1, now)
+		require.NoError(t, err)
+		require.Equal(t, allowed, d.Allowed)
+	}
+
+	// Buckets are kept per key.
+	d, err := m.Take(ctx, "b", l, 1, now)
+	require.NoError(t, err)
+	require.True(t, d.Allowed)
+	require.Equal(t, 2, m.Len())
+
+	// Buckets which have refilled are discarded.
+	_, err = m.Take(ctx, "c", l, 1, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, m.Len())
+}
+
+func TestCosts(t *testing.T) {
+	sdl, err := schema.String()
+	require.NoError(t, err)
+
+	costs, err := ratelimit.NewCosts(sdl)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		query string
+		want  int
+	}{
+		{`{ __schema { types { name } } }`, 0},
+		{`{ films { title } }`, 10},
+		{`{ films { title director } planets(name: "Tatooine") { name } }`, 20},
+		{`{ films { title characters { name homeworld { name } } } }`, 10 + 10*10*2},
+		{`{ starships { ...Pilots } } fragment Pilots on Starship { pilots { name } }`, 10 + 10*10},
+		{`{ films { ... on Film { species { name } } } }`, 10 + 10*10},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := costs.Estimate(tt.query, "", nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	costs.ListSize = 2
+	got, err := costs.Estimate(`{ films { characters { name } } }`, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2+2*2, got)
+
+	_, err = costs.Estimate(`{ films`, "", nil)
+	require.Error(t, err)
+
+	// Federation's _entities holds an entity per representation, whatever the list size.
+	entities := `query ($r: [_Any!]!) { _entities(representations: $r) { ... on Film { characters { name } } } }`
+	representations := make([]interface{}, 50)
+
+	got, err = costs.Estimate(entities, "", map[string]interface{}{"r": representations})
+	require.NoError(t, err)
+	require.Equal(t, 50+50*2, got)
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	l := &ratelimit.Limiter{
+		Store: ratelimit.NewMemory(),
+		Anonymous: ratelimit.Tier{
+			Requests: ratelimit.Limit{Capacity: 2, Per: time.Minute},
+			Cost:     ratelimit.Limit{Capacity: 100, Per: time.Minute},
+		},
+		Authenticated: ratelimit.Tier{Requests: ratelimit.Limit{Capacity: 10, Per: time.Minute}},
+		Tiers:         map[string]ratelimit.Tier{"internal": {}},
+	}
+
+	require.Equal(t, l.Anonymous, l.Tier(nil))
+	require.Equal(t, l.Authenticated, l.Tier(&auth.Principal{Roles: []string{"partner"}}))
+	require.Equal(t, ratelimit.Tier{}, l.Tier(&auth.Principal{Roles: []string{"partner", "INTERNAL"}}))
+
+	// A request rejected for its cost spends a request, but none of the cost.
+	res, err := l.Allow(ctx, "ip:a", l.Anonymous, 80, now)
+	require.NoError(t, err)
+	require.True(t, res.Allowed())
+
+	res, err = l.Allow(ctx, "ip:a", l.Anonymous, 80, now)
+	require.NoError(t, err)
+	require.False(t, res.Allowed())
+	require.True(t, res.Requests.Allowed)
+	require.Equal(t, 20, res.Cost.Remaining)
+	require.Equal(t, 36*time.Second, res.RetryAfter())
+
+	// Without requests left, the cost is not spent.
+	res, err = l.Allow(ctx, "ip:a", l.Anonymous, 10, now)
+	require.NoError(t, err)
+	require.False(t, res.Allowed())
+	require.Equal(t, 30*time.Second, res.RetryAfter())
+
+	res, err = l.Allow(ctx, "ip:a", l.Anonymous, 10, now.Add(30*time.Second))
+	require.NoError(t, err)
+	require.True(t, res.Allowed())
+	require.Equal(t, 60, res.Cost.Remaining)
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A Store keeps the buckets of every client. Stores shared by several servers, such as one backed
+// by Redis, let clients be limited across all of them.
+type Store interface {
+	// Take spends n tokens from the bucket with the key under the limit, as Limit.Take does. The
+	// bucket must be read and updated atomically.
+	Take(ctx context.Context, key string, l Limit, n float64, now time.Time) (Decision, error)
+}
+
+// sweepInterval is how often a Memory store discards buckets which have refilled completely.
+const sweepInterval = time.Minute
+
+// A Memory store keeps buckets in memory. It is safe for concurrent use.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	swept   time.Time
+}
+
+type memoryBucket struct {
+	Bucket
+	// full is when the bucket will have refilled completely, after which it is the same as a new
+	// bucket and can be discarded.
+	full time.Time
+}
+
+// NewMemory creates an empty Memory store.
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]*memoryBucket{}}
+}
+
+// Take implements Store.
+func (m *Memory) Take(_ context.Context, key string, l Limit, n float64, now time.Time) (Decision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.swept) >= sweepInterval {
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+	}
+
+	var d Decision
+	b.Bucket, d = l.Take(b.Bucket, n, now)
+	b.full = now.Add(d.Reset)
+
+	if !l.Unlimited() {
+		m.buckets[key] = b
+	}
+
+	return d, nil
+}
+
+// Len returns the number of buckets kept.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.buckets)
+}
+
+func (m *Memory) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+
+	m.swept = now
+}
@@ -0,0 +1,79 @@
+package resolver_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/resolver"
+	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// anyResource serves a page holding a single resource for every search.
+type anyResource struct{}
+
+func (anyResource) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := fmt.Sprintf(`{"count": 1, "results": [{"url": "https://swapi.dev%s1/", "cost_in_credits": "1000"}]}`, r.URL.Path)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+// TestAuthorization checks that the resolver of every field restricted with @auth enforces it,
+// since graphql-go does not execute the directive itself.
+func TestAuthorization(t *testing.T) {
+	s, err := schema.String()
+	require.NoError(t, err)
+
+	rules, err := auth.NewRules(s)
+	require.NoError(t, err)
+	require.NotEmpty(t, rules.Coordinates())
+
+	c := swapi.NewClient(&http.Client{Transport: anyResource{}})
+
+	root, err := resolver.NewRoot(c)
+	require.NoError(t, err)
+
+	exec := graphql.MustParseSchema(s, root, graphql.UseStringDescriptions())
+	types := inspectTypes(t)
+
+	for _, coordinate := range rules.Coordinates() {
+		typ, field, _ := strings.Cut(coordinate, ".")
+
+		// Reach the type from a field of the query type.
+		var query string
+		for name, target := range fieldTypes(types["Query"]) {
+			if target == typ {
+				query = fmt.Sprintf("{ %s { %s } }", name, field)
+				break
+			}
+		}
+		require.NotEmpty(t, query, "%s is not reachable from the query type", typ)
+
+		for _, p := range []*auth.Principal{nil, {Subject: "partner", Roles: []string{"PARTNER"}}, {Subject: "staff", Roles: []string{"INTERNAL"}}} {
+			ctx := loader.Initialize(c, loader.Settings{}).Attach(context.Background())
+			ctx = rules.Attach(auth.Attach(ctx, p))
+
+			res := exec.Exec(ctx, query, "", nil)
+			errors.Annotate(res.Errors, "")
+
+			if rules.Allowed(p, coordinate) {
+				require.Empty(t, res.Errors, "%s as %v", coordinate, p)
+				continue
+			}
+
+			require.NotEmpty(t, res.Errors, "%s is not enforced", coordinate)
+			for _, err := range res.Errors {
+				require.Equal(t, errors.Forbidden, err.Extensions["code"], "%s: %s", coordinate, err.Message)
+			}
+		}
+	}
+}
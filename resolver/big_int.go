@@ -0,0 +1,49 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// BigInt is an arbitrary-precision integer which is always serialized as a decimal string.
+type BigInt struct {
+	big.Int
+}
+
+// ImplementsGraphQLType maps this custom Go type to the BigInt scalar type in the schema.
+func (BigInt) ImplementsGraphQLType(name string) bool {
+	return name == "BigInt"
+}
+
+// UnmarshalGraphQL is a custom unmarshaler for BigInt used when BigInt is an input.
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		b.SetInt64(int64(input))
+	case int64:
+		b.SetInt64(input)
+	case string:
+		if _, ok := b.SetString(input, 10); !ok {
+			return fmt.Errorf("invalid BigInt: %q", input)
+		}
+	default:
+		return fmt.Errorf("wrong type for BigInt: %T", input)
+	}
+
+	return nil
+}
+
+// MarshalJSON is a custom marshaler for BigInt used when BigInt is an output.
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// nullableBigInt converts an optional big.Int into an optional BigInt.
+func nullableBigInt(i *big.Int) *BigInt {
+	if i == nil {
+		return nil
+	}
+
+	return &BigInt{Int: *i}
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/units"
+)
+
+// DurationResolver resolves the Duration type.
+type DurationResolver struct {
+	duration normalize.Duration
+	field    normalize.Field
+}
+
+func newDuration(f normalize.Field, raw string) *DurationResolver {
+	return &DurationResolver{duration: normalize.ParseDuration(raw), field: f}
+}
+
+// Value resolves the length of the duration in the requested unit.
+func (r *DurationResolver) Value(ctx context.Context, args UnitArgs) (*float64, error) {
+	d := r.duration
+
+	normalize.Check(ctx, r.field, d.Number)
+	// An unknown duration converts to null, but the requested unit is still checked.
+	if !d.Known {
+		return convert(args, nil, units.Hour)
+	}
+
+	if d.Unit == "" {
+		normalize.Warn(ctx, r.field, d.Raw, "duration has no unit")
+		return convert(args, nil, units.Hour)
+	}
+
+	from, err := units.Parse[units.Time](d.Unit)
+	if err != nil {
+		normalize.Warn(ctx, r.field, d.Raw, "unknown unit of time: "+d.Unit)
+		return convert(args, nil, units.Hour)
+	}
+
+	return convert(args, &d.Min, from)
+}
+
+// Text resolves the duration as it was originally written.
+func (r *DurationResolver) Text() string {
+	return r.duration.Raw
+}
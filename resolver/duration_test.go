@@ -0,0 +1,48 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/normalize"
+)
+
+func TestDurationResolver(t *testing.T) {
+	f := normalize.Field{Type: "Starship", ID: "9", Name: "consumables"}
+
+	cases := []struct {
+		input    string
+		unit     string
+		expected float64
+	}{
+		{"2 months", "DAY", 60.875},
+		{"1 year", "MONTH", 12},
+		{"3 years", "YEAR", 3},
+		{"2 weeks", "HOUR", 336},
+		{"736 days", "STANDARD_GALACTIC_YEAR", 2},
+	}
+
+	for _, c := range cases {
+		v, err := newDuration(f, c.input).Value(context.Background(), UnitArgs{Unit: c.unit})
+		require.NoError(t, err)
+		require.NotNil(t, v)
+		require.InDelta(t, c.expected, *v, 1e-9, "%q in %s", c.input, c.unit)
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		ctx, report := normalize.Attach(context.Background())
+
+		r := newDuration(f, "live food tanks")
+		v, err := r.Value(ctx, UnitArgs{Unit: "DAY"})
+		require.NoError(t, err)
+		require.Nil(t, v)
+		require.Equal(t, "live food tanks", r.Text())
+		require.Len(t, report.Warnings(), 1)
+
+		// The requested unit is checked even when the duration is unknown.
+		_, err = r.Value(ctx, UnitArgs{Unit: "FORTNIGHT"})
+		require.EqualError(t, err, "unknown TimeUnit: FORTNIGHT")
+	})
+}
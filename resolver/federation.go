@@ -0,0 +1,180 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+)
+
+// ServiceResolver resolves the _Service type, which describes this API to a federation router.
+type ServiceResolver struct {
+	sdl string
+}
+
+// SDL resolves the SDL of this subgraph.
+func (r ServiceResolver) SDL() *string {
+	return &r.sdl
+}
+
+// Service resolves the "_service" query.
+func (r QueryResolver) Service() ServiceResolver {
+	return ServiceResolver{sdl: r.sdl}
+}
+
+// Representation is the _Any scalar: the __typename and key fields of an entity, as a federation
+// router sends them.
+type Representation struct {
+	Typename string
+	// ID is the key field. It is empty when the representation has no ID.
+	ID string
+}
+
+// ImplementsGraphQLType maps this type to the _Any scalar in the schema.
+func (Representation) ImplementsGraphQLType(name string) bool {
+	return name == "_Any"
+}
+
+// UnmarshalGraphQL reads a representation from an object. Unknown types and missing IDs are
+// reported by the "_entities" query, so the errors carry the position of the representation.
+func (r *Representation) UnmarshalGraphQL(input interface{}) error {
+	m, ok := input.(map[string]interface{})
+	if !ok {
+		return errors.WrongType(map[string]interface{}{}, input)
+	}
+
+	r.Typename, _ = m["__typename"].(string)
+
+	switch id := m["id"].(type) {
+	case string:
+		r.ID = id
+	case int32, int64, float64:
+		r.ID = fmt.Sprint(id)
+	}
+
+	return nil
+}
+
+// EntitiesQueryArgs are the arguments for the "_entities" query.
+type EntitiesQueryArgs struct {
+	Representations []Representation
+}
+
+// entityKinds maps the entity types to the kinds of SWAPI resources they are loaded from.
+var entityKinds = map[string]string{
+	"Film":     "films",
+	"Person":   "people",
+	"Planet":   "planets",
+	"Species":  "species",
+	"Starship": "starships",
+	"Vehicle":  "vehicles",
+}
+
+// Entities resolves the "_entities" query: the entity each representation identifies, in order.
+//
+// The entities are resolved lazily from their URLs, like the resources of a list, so the router's
+// lookups of many entities of a type are loaded in a single batch, and a lookup which only selects
+// the key fields loads nothing at all.
+//
+// The entity of an invalid representation is null, and its error is reported at its index, so the
+// router still gets the other entities.
+func (r QueryResolver) Entities(ctx context.Context, args EntitiesQueryArgs) []*EntityResolver {
+	var errs errors.Errors
+
+	entities := make([]*EntityResolver, len(args.Representations))
+
+	for i, rep := range args.Representations {
+		e, err := r.entity(ctx, rep)
+		if err != nil {
+			errs = append(errs, errors.WithIndex(errors.WithCode(err, errors.BadUserInput), i))
+			continue
+		}
+
+		entities[i] = e
+	}
+
+	errors.Report(ctx, errs.Err(), "_entities")
+
+	return entities
+}
+
+func (r QueryResolver) entity(ctx context.Context, rep Representation) (*EntityResolver, error) {
+	kind, ok := entityKinds[rep.Typename]
+	if !ok {
+		return nil, errors.Errorf("unknown entity type %q", rep.Typename)
+	}
+
+	if _, err := strconv.ParseUint(rep.ID, 10, 64); err != nil {
+		return nil, errors.Errorf("invalid %s id %q", rep.Typename, rep.ID)
+	}
+
+	url := r.client.URL(kind, rep.ID)
+
+	var (
+		entity interface{}
+		err    error
+	)
+
+	switch rep.Typename {
+	case "Film":
+		entity, err = NewFilm(ctx, NewFilmArgs{URL: url})
+	case "Person":
+		entity, err = NewPerson(ctx, NewPersonArgs{URL: url})
+	case "Planet":
+		entity, err = NewPlanet(ctx, NewPlanetArgs{URL: url})
+	case "Species":
+		entity, err = NewSpecies(ctx, NewSpeciesArgs{URL: url})
+	case "Starship":
+		entity, err = NewStarship(ctx, NewStarshipArgs{URL: url})
+	case "Vehicle":
+		entity, err = NewVehicle(ctx, NewVehicleArgs{URL: url})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &EntityResolver{entity: entity}, nil
+}
+
+// EntityResolver resolves the _Entity union.
+type EntityResolver struct {
+	entity interface{}
+}
+
+// ToFilm resolves the entity when it is a film.
+func (r *EntityResolver) ToFilm() (*FilmResolver, bool) {
+	f, ok := r.entity.(*FilmResolver)
+	return f, ok
+}
+
+// ToPerson resolves the entity when it is a person.
+func (r *EntityResolver) ToPerson() (*PersonResolver, bool) {
+	p, ok := r.entity.(*PersonResolver)
+	return p, ok
+}
+
+// ToPlanet resolves the entity when it is a planet.
+func (r *EntityResolver) ToPlanet() (*PlanetResolver, bool) {
+	p, ok := r.entity.(*PlanetResolver)
+	return p, ok
+}
+
+// ToSpecies resolves the entity when it is a species.
+func (r *EntityResolver) ToSpecies() (*SpeciesResolver, bool) {
+	s, ok := r.entity.(*SpeciesResolver)
+	return s, ok
+}
+
+// ToStarship resolves the entity when it is a starship.
+func (r *EntityResolver) ToStarship() (*StarshipResolver, bool) {
+	s, ok := r.entity.(*StarshipResolver)
+	return s, ok
+}
+
+// ToVehicle resolves the entity when it is a vehicle.
+func (r *EntityResolver) ToVehicle() (*VehicleResolver, bool) {
+	v, ok := r.entity.(*VehicleResolver)
+	return v, ok
+}
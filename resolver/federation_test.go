@@ -0,0 +1,90 @@
+package resolver_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+)
+
+func TestService(t *testing.T) {
+	s, _, c := newSelectionSchema(t)
+
+	ctx := loader.Initialize(c, loader.Settings{}).Attach(context.Background())
+	res := s.Exec(ctx, `{ _service { sdl } }`, "", nil)
+	require.Empty(t, res.Errors)
+
+	var data struct {
+		Service struct{ SDL string } `json:"_service"`
+	}
+	require.NoError(t, json.Unmarshal(res.Data, &data))
+
+	sdl := data.Service.SDL
+	require.Contains(t, sdl, `@link(url: "https://specs.apollo.dev/federation/v2.0"`)
+	require.Contains(t, sdl, `type Film @key(fields: "id")`)
+	require.NotContains(t, sdl, "_entities")
+	require.NotContains(t, sdl, "_Entity")
+}
+
+func TestEntities(t *testing.T) {
+	s, api, c := newSelectionSchema(t)
+
+	const query = `query ($representations: [_Any!]!) {
+		_entities(representations: $representations) {
+			__typename
+			... on Person { id name }
+			... on Starship { id }
+		}
+	}`
+
+	vars := map[string]interface{}{
+		"representations": []interface{}{
+			map[string]interface{}{"__typename": "Person", "id": "1"},
+			map[string]interface{}{"__typename": "Starship", "id": "9"},
+			map[string]interface{}{"__typename": "Person", "id": "2"},
+		},
+	}
+
+	ctx := loader.Initialize(c, loader.Settings{}).Attach(context.Background())
+	res := s.Exec(ctx, query, "", vars)
+	require.Empty(t, res.Errors)
+
+	require.JSONEq(t, `{"_entities": [
+		{"__typename": "Person", "id": "1", "name": "Person /api/people/1/"},
+		{"__typename": "Starship", "id": "9"},
+		{"__typename": "Person", "id": "2", "name": "Person /api/people/2/"}
+	]}`, string(res.Data))
+
+	// Only the people are loaded; the starship's ID is its key.
+	require.EqualValues(t, 2, api.calls)
+}
+
+func TestEntitiesInvalidRepresentation(t *testing.T) {
+	s, _, c := newSelectionSchema(t)
+
+	ctx := loader.Initialize(c, loader.Settings{}).Attach(context.Background())
+	ctx, collector := errors.Collect(ctx)
+
+	res := s.Exec(ctx, `query ($representations: [_Any!]!) {
+		_entities(representations: $representations) { __typename }
+	}`, "", map[string]interface{}{
+		"representations": []interface{}{
+			map[string]interface{}{"__typename": "Person", "id": "1"},
+			map[string]interface{}{"__typename": "Droid", "id": "2"},
+		},
+	})
+
+	// The valid representation still resolves.
+	require.Empty(t, res.Errors)
+	require.JSONEq(t, `{"_entities": [{"__typename": "Person"}, null]}`, string(res.Data))
+
+	errs := errors.Expand(collector.Errors())
+	require.Len(t, errs, 1)
+	require.Equal(t, []interface{}{"_entities", 1}, errs[0].Path)
+	require.Equal(t, errors.BadUserInput, errors.Classify(errs[0]))
+	require.Contains(t, errs[0].Message, `unknown entity type "Droid"`)
+}
@@ -0,0 +1,193 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// FilmResolver resolves the Film type.
+// The film is only loaded once a field other than the ID is resolved.
+type FilmResolver struct {
+	film *lazy[swapi.Film]
+}
+
+// NewFilmArgs are the arguments for NewFilm.
+// The film is loaded from URL unless Film is given.
+type NewFilmArgs struct {
+	Film swapi.Film
+	URL  string
+}
+
+// NewFilmsArgs are the arguments for NewFilms.
+type NewFilmsArgs struct {
+	Page swapi.FilmPage
+	URLs []string
+}
+
+// NewFilm creates a resolver for a single film.
+func NewFilm(ctx context.Context, args NewFilmArgs) (*FilmResolver, error) {
+	switch {
+	case args.Film.URL != "":
+		return &FilmResolver{film: loaded(args.Film.URL, args.Film)}, nil
+	case args.URL != "":
+		return &FilmResolver{film: deferred(args.URL, loader.LoadFilm)}, nil
+	default:
+		return nil, errors.UnableToResolve
+	}
+}
+
+// NewFilms creates resolvers for a list of URLs followed by a search results page.
+//
+// Each resolver keeps the position of its URL. The resources are loaded lazily, so a resource which
+// fails to load only fails the fields resolved on it, and the errors carry the resource's index.
+func NewFilms(ctx context.Context, args NewFilmsArgs) (*[]*FilmResolver, error) {
+	err := loader.PrimeFilms(ctx, args.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*FilmResolver, 0, len(args.URLs)+len(args.Page.Films))
+
+	for _, url := range args.URLs {
+		resolvers = append(resolvers, &FilmResolver{film: deferred(url, loader.LoadFilm)})
+	}
+
+	for _, v := range args.Page.Films {
+		resolvers = append(resolvers, &FilmResolver{film: loaded(v.URL, v)})
+	}
+
+	return &resolvers, nil
+}
+
+// field identifies one of this film's attributes for data-quality warnings.
+func (r *FilmResolver) field(name string) normalize.Field {
+	return normalize.Field{Type: "Film", ID: string(r.ID()), Name: name}
+}
+
+// ID resolves the id field.
+func (r *FilmResolver) ID() graphql.ID {
+	return extractID(r.film.url)
+}
+
+// Title resolves the title field.
+func (r *FilmResolver) Title(ctx context.Context) (string, error) {
+	film, err := r.film.get(ctx)
+	return film.Title, err
+}
+
+// OpeningCrawl resolves the openingCrawl field.
+func (r *FilmResolver) OpeningCrawl(ctx context.Context) (string, error) {
+	film, err := r.film.get(ctx)
+	return film.OpeningCrawl, err
+}
+
+// Director resolves the director field.
+func (r *FilmResolver) Director(ctx context.Context) (string, error) {
+	film, err := r.film.get(ctx)
+	return film.DirectorName, err
+}
+
+// DirectorName resolves the directorName field.
+func (r *FilmResolver) DirectorName(ctx context.Context) (string, error) {
+	film, err := r.film.get(ctx)
+	return film.DirectorName, err
+}
+
+// ProducerNames resolves the producerNames field.
+func (r *FilmResolver) ProducerNames(ctx context.Context) ([]string, error) {
+	film, err := r.film.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(film.ProducerNames), nil
+}
+
+// Species resolves the species field.
+func (r *FilmResolver) Species(ctx context.Context) (*[]*SpeciesResolver, error) {
+	film, err := r.film.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSpeciesList(ctx, NewSpeciesListArgs{URLs: film.SpeciesURLs})
+}
+
+// Starships resolves the starships field.
+func (r *FilmResolver) Starships(ctx context.Context) (*[]*StarshipResolver, error) {
+	film, err := r.film.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStarships(ctx, NewStarshipsArgs{URLs: film.StarshipURLs})
+}
+
+// Vehicles resolves the vehicles field.
+func (r *FilmResolver) Vehicles(ctx context.Context) (*[]*VehicleResolver, error) {
+	film, err := r.film.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVehicles(ctx, NewVehiclesArgs{URLs: film.VehicleURLs})
+}
+
+// Characters resolves the characters field.
+func (r *FilmResolver) Characters(ctx context.Context) (*[]*PersonResolver, error) {
+	film, err := r.film.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPeople(ctx, NewPeopleArgs{URLs: film.CharacterURLs})
+}
+
+// Planets resolves the planets field.
+func (r *FilmResolver) Planets(ctx context.Context) (*[]*PlanetResolver, error) {
+	film, err := r.film.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPlanets(ctx, NewPlanetsArgs{URLs: film.PlanetURLs})
+}
+
+// CreatedAt resolves the createdAt field.
+func (r *FilmResolver) CreatedAt(ctx context.Context) (graphql.Time, error) {
+	film, err := r.film.get(ctx)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, film.CreatedAt)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	return graphql.Time{Time: t}, nil
+}
+
+// EditedAt resolves the editedAt field.
+func (r *FilmResolver) EditedAt(ctx context.Context) (*graphql.Time, error) {
+	film, err := r.film.get(ctx)
+	if err != nil || film.EditedAt == "" {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, film.EditedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphql.Time{Time: t}, nil
+}
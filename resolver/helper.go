@@ -45,3 +45,17 @@ func nullableStr(s string) *string {
 
 	return &s
 }
+
+// splitList splits a comma-separated SWAPI value into its trimmed, non-empty elements.
+func splitList(s string) []string {
+	parts := strings.Split(s, ",")
+	list := make([]string, 0, len(parts))
+
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+
+	return list
+}
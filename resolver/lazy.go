@@ -0,0 +1,48 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+)
+
+// lazy is a SWAPI resource which is identified by its URL and loaded the first time it is needed.
+//
+// Every resource ID is derived from its URL, so a resolver whose selection set only contains id
+// and __typename never loads the resource at all. Loads go through the request's dataloaders, so
+// the resources needed by sibling resolvers are still fetched in a single batch.
+type lazy[T any] struct {
+	url  string
+	load func(context.Context, string) (T, error)
+
+	once  sync.Once
+	value T
+	err   error
+}
+
+// loaded returns a lazy resource whose value is already known.
+func loaded[T any](url string, v T) *lazy[T] {
+	return &lazy[T]{url: url, value: v}
+}
+
+// deferred returns a lazy resource which is loaded from its URL on first use.
+// A resource without a URL cannot be loaded, so resolving any of its fields fails.
+func deferred[T any](url string, load func(context.Context, string) (T, error)) *lazy[T] {
+	if url == "" {
+		return &lazy[T]{err: errors.UnableToResolve}
+	}
+
+	return &lazy[T]{url: url, load: load}
+}
+
+// get returns the resource, loading it if it has not been loaded yet.
+func (l *lazy[T]) get(ctx context.Context) (T, error) {
+	l.once.Do(func() {
+		if l.load != nil {
+			l.value, l.err = l.load(ctx, l.url)
+		}
+	})
+
+	return l.value, l.err
+}
@@ -1 +0,0 @@
-package resolver_test
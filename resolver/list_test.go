@@ -0,0 +1,42 @@
+package resolver_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+)
+
+func TestPartiallyFailedList(t *testing.T) {
+	s, api, c := newSelectionSchema(t)
+	api.missing = "/api/people/3/"
+
+	ctx := loader.Initialize(c, loader.Settings{}).Attach(context.Background())
+	res := s.Exec(ctx, `{ films { characters { id name } } }`, "", nil)
+
+	var data struct {
+		Films []struct {
+			Characters []*struct {
+				ID   string
+				Name string
+			}
+		}
+	}
+
+	require.NoError(t, json.Unmarshal(res.Data, &data))
+
+	// The failed character is null and keeps its position; the others are unaffected.
+	chars := data.Films[0].Characters
+	require.Len(t, chars, characters)
+	require.Nil(t, chars[2])
+	require.NotNil(t, chars[1])
+	require.Equal(t, "4", chars[3].ID)
+
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, []interface{}{"films", 0, "characters", 2, "name"}, res.Errors[0].Path)
+	require.Equal(t, errors.NotFound, errors.Classify(res.Errors[0]))
+}
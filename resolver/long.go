@@ -0,0 +1,62 @@
+package resolver
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Long is a 64-bit signed integer.
+//
+// Long is always serialized as a JSON number, so clients can rely on a single wire type. JavaScript
+// numbers lose precision beyond 2^53, so fields which may exceed it have a BigInt alternative.
+type Long int64
+
+// maxSafeInteger is the largest integer a JavaScript number can represent exactly.
+// Larger numbers given as input are rejected, since they may already have lost precision.
+const maxSafeInteger = 1<<53 - 1
+
+// ImplementsGraphQLType maps this custom Go type to the Long scalar type in the schema.
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL is a custom unmarshaler for Long used when Long is an input.
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		*l = Long(input)
+	case int64:
+		*l = Long(input)
+	case float64:
+		if input != math.Trunc(input) || math.Abs(input) > maxSafeInteger {
+			return fmt.Errorf("invalid Long: %v", input)
+		}
+		*l = Long(input)
+	case string:
+		i, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Long: %q", input)
+		}
+		*l = Long(i)
+	default:
+		return fmt.Errorf("wrong type for Long: %T", input)
+	}
+
+	return nil
+}
+
+// MarshalJSON is a custom marshaler for Long used when Long is an output.
+func (l Long) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(l), 10)), nil
+}
+
+// nullableLong converts an optional int64 into an optional Long.
+func nullableLong(i *int64) *Long {
+	if i == nil {
+		return nil
+	}
+
+	l := Long(*i)
+	return &l
+}
@@ -0,0 +1,66 @@
+package resolver_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/resolver"
+)
+
+func TestLong(t *testing.T) {
+	t.Run("MarshalJSON", func(t *testing.T) {
+		cases := []struct {
+			input    resolver.Long
+			expected string
+		}{
+			{0, `0`},
+			{1000000000000, `1000000000000`},
+			{1<<53 - 1, `9007199254740991`},
+			{1 << 53, `9007199254740992`},
+			{-(1 << 53), `-9007199254740992`},
+			{1<<63 - 1, `9223372036854775807`},
+		}
+
+		for _, c := range cases {
+			b, err := json.Marshal(c.input)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, string(b))
+		}
+	})
+
+	t.Run("UnmarshalGraphQL", func(t *testing.T) {
+		cases := []struct {
+			input    interface{}
+			expected resolver.Long
+		}{
+			{int32(7), 7},
+			{int64(1000000000000), 1000000000000},
+			{float64(1e12), 1000000000000},
+			{"9007199254740992", 1 << 53},
+		}
+
+		for _, c := range cases {
+			var l resolver.Long
+			require.NoError(t, l.UnmarshalGraphQL(c.input))
+			require.Equal(t, c.expected, l)
+		}
+
+		var l resolver.Long
+		require.Error(t, l.UnmarshalGraphQL(1.5))
+		require.Error(t, l.UnmarshalGraphQL("one"))
+		require.Error(t, l.UnmarshalGraphQL(true))
+	})
+}
+
+func TestBigInt(t *testing.T) {
+	var b resolver.BigInt
+	require.NoError(t, b.UnmarshalGraphQL("1000000000000000000000000"))
+
+	out, err := json.Marshal(b)
+	require.NoError(t, err)
+	require.Equal(t, `"1000000000000000000000000"`, string(out))
+
+	require.Error(t, b.UnmarshalGraphQL("1e6"))
+}
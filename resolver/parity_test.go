@@ -0,0 +1,139 @@
+package resolver_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/introspection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// swapiTypes maps each GraphQL object type to the swapi struct it is resolved from, along with the
+// schema field names of any JSON attributes that are not simply camel-cased.
+var swapiTypes = map[string]struct {
+	model  interface{}
+	fields map[string]string
+}{
+	"Film": {swapi.Film{}, map[string]string{
+		"episode_id": "episode",
+		"producer":   "producerNames",
+	}},
+	"Person": {swapi.Person{}, nil},
+	"Planet": {swapi.Planet{}, map[string]string{
+		"climate":       "climates",
+		"surface_water": "surfaceWaterPercentage",
+		"terrain":       "terrains",
+	}},
+	"Species": {swapi.Species{}, map[string]string{
+		"people": "characters",
+	}},
+	"Starship": {swapi.Starship{}, map[string]string{
+		"MGLT":                   "maxMegalightsPerHour",
+		"cost_in_credits":        "cost",
+		"crew":                   "crewSize",
+		"manufacturer":           "manufacturers",
+		"max_atmosphering_speed": "maxAtmosphericSpeed",
+		"starship_class":         "class",
+	}},
+	"Vehicle": {swapi.Vehicle{}, map[string]string{
+		"cost_in_credits":        "cost",
+		"crew":                   "crewSize",
+		"manufacturer":           "manufacturers",
+		"max_atmosphering_speed": "maxAtmosphericSpeed",
+		"vehicle_class":          "class",
+	}},
+}
+
+// commonFields are the schema field names of attributes shared by every SWAPI resource.
+var commonFields = map[string]string{
+	"created": "createdAt",
+	"edited":  "editedAt",
+	"url":     "id",
+}
+
+func TestSchemaParity(t *testing.T) {
+	types := inspectTypes(t)
+
+	t.Run("every swapi attribute has a schema field", func(t *testing.T) {
+		for name, m := range swapiTypes {
+			fields := fieldTypes(types[name])
+
+			rt := reflect.TypeOf(m.model)
+			for i := 0; i < rt.NumField(); i++ {
+				attr := strings.Split(rt.Field(i).Tag.Get("json"), ",")[0]
+
+				field, ok := m.fields[attr]
+				if !ok {
+					field, ok = commonFields[attr]
+				}
+				if !ok {
+					field = camelCase(attr)
+				}
+
+				_, ok = fields[field]
+				require.True(t, ok, "swapi.%s.%s (%q) has no %s.%s schema field", rt.Name(), rt.Field(i).Name, attr, name, field)
+			}
+		}
+	})
+
+	t.Run("every relationship is traversable in both directions", func(t *testing.T) {
+		for name := range swapiTypes {
+			for field, target := range fieldTypes(types[name]) {
+				if _, ok := swapiTypes[target]; !ok || target == name {
+					continue
+				}
+
+				found := false
+				for _, back := range fieldTypes(types[target]) {
+					found = found || back == name
+				}
+
+				require.True(t, found, "%s.%s links to %s, but %s has no field linking back to %s", name, field, target, target, name)
+			}
+		}
+	})
+}
+
+func inspectTypes(t *testing.T) map[string]*introspection.Type {
+	s, err := schema.String()
+	require.NoError(t, err)
+
+	types := make(map[string]*introspection.Type)
+	for _, typ := range graphql.MustParseSchema(s, nil, graphql.UseStringDescriptions()).Inspect().Types() {
+		types[*typ.Name()] = typ
+	}
+
+	return types
+}
+
+// fieldTypes maps the field names of an object type to the names of their unwrapped types.
+func fieldTypes(typ *introspection.Type) map[string]string {
+	fields := make(map[string]string)
+
+	for _, f := range *typ.Fields(&struct{ IncludeDeprecated bool }{true}) {
+		ft := f.Type()
+		for ft.OfType() != nil {
+			ft = ft.OfType()
+		}
+
+		fields[f.Name()] = *ft.Name()
+	}
+
+	return fields
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,211 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// PersonResolver resolves the Person type.
+// The person is only loaded once a field other than the ID is resolved.
+type PersonResolver struct {
+	person *lazy[swapi.Person]
+}
+
+// NewPersonArgs are the arguments for NewPerson.
+// The person is loaded from URL unless Person is given.
+type NewPersonArgs struct {
+	Person swapi.Person
+	URL    string
+}
+
+// NewPeopleArgs are the arguments for NewPeople.
+type NewPeopleArgs struct {
+	Page swapi.PersonPage
+	URLs []string
+}
+
+// NewPerson creates a resolver for a single person.
+func NewPerson(ctx context.Context, args NewPersonArgs) (*PersonResolver, error) {
+	switch {
+	case args.Person.URL != "":
+		return &PersonResolver{person: loaded(args.Person.URL, args.Person)}, nil
+	case args.URL != "":
+		return &PersonResolver{person: deferred(args.URL, loader.LoadPerson)}, nil
+	default:
+		return nil, errors.UnableToResolve
+	}
+}
+
+// NewPeople creates resolvers for a list of URLs followed by a search results page.
+//
+// Each resolver keeps the position of its URL. The resources are loaded lazily, so a resource which
+// fails to load only fails the fields resolved on it, and the errors carry the resource's index.
+func NewPeople(ctx context.Context, args NewPeopleArgs) (*[]*PersonResolver, error) {
+	err := loader.PrimePeople(ctx, args.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*PersonResolver, 0, len(args.URLs)+len(args.Page.People))
+
+	for _, url := range args.URLs {
+		resolvers = append(resolvers, &PersonResolver{person: deferred(url, loader.LoadPerson)})
+	}
+
+	for _, v := range args.Page.People {
+		resolvers = append(resolvers, &PersonResolver{person: loaded(v.URL, v)})
+	}
+
+	return &resolvers, nil
+}
+
+// field identifies one of this person's attributes for data-quality warnings.
+func (r *PersonResolver) field(name string) normalize.Field {
+	return normalize.Field{Type: "Person", ID: string(r.ID()), Name: name}
+}
+
+// ID resolves the id field.
+func (r *PersonResolver) ID() graphql.ID {
+	return extractID(r.person.url)
+}
+
+// Name resolves the name field.
+func (r *PersonResolver) Name(ctx context.Context) (string, error) {
+	person, err := r.person.get(ctx)
+	return person.Name, err
+}
+
+// BirthYear resolves the birthYear field.
+func (r *PersonResolver) BirthYear(ctx context.Context) (string, error) {
+	person, err := r.person.get(ctx)
+	return person.BirthYear, err
+}
+
+// EyeColor resolves the eyeColor field.
+func (r *PersonResolver) EyeColor(ctx context.Context) (*string, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nullableStr(person.EyeColor), nil
+}
+
+// Gender resolves the gender field.
+func (r *PersonResolver) Gender(ctx context.Context) (*string, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nullableStr(person.Gender), nil
+}
+
+// HairColor resolves the hairColor field.
+func (r *PersonResolver) HairColor(ctx context.Context) (*string, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nullableStr(person.HairColor), nil
+}
+
+// SkinColor resolves the skinColor field.
+func (r *PersonResolver) SkinColor(ctx context.Context) (*string, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nullableStr(person.SkinColor), nil
+}
+
+// Homeworld resolves the homeworld field.
+func (r *PersonResolver) Homeworld(ctx context.Context) (*PlanetResolver, error) {
+	person, err := r.person.get(ctx)
+	if err != nil || person.HomeworldURL == "" {
+		return nil, err
+	}
+
+	return NewPlanet(ctx, NewPlanetArgs{URL: person.HomeworldURL})
+}
+
+// Films resolves the films field.
+func (r *PersonResolver) Films(ctx context.Context) (*[]*FilmResolver, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilms(ctx, NewFilmsArgs{URLs: person.FilmURLs})
+}
+
+// Species resolves the species field.
+func (r *PersonResolver) Species(ctx context.Context) (*[]*SpeciesResolver, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSpeciesList(ctx, NewSpeciesListArgs{URLs: person.SpeciesURLs})
+}
+
+// Starships resolves the starships field.
+func (r *PersonResolver) Starships(ctx context.Context) (*[]*StarshipResolver, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStarships(ctx, NewStarshipsArgs{URLs: person.StarshipURLs})
+}
+
+// Vehicles resolves the vehicles field.
+func (r *PersonResolver) Vehicles(ctx context.Context) (*[]*VehicleResolver, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVehicles(ctx, NewVehiclesArgs{URLs: person.VehicleURLs})
+}
+
+// CreatedAt resolves the createdAt field.
+func (r *PersonResolver) CreatedAt(ctx context.Context) (graphql.Time, error) {
+	person, err := r.person.get(ctx)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, person.CreatedAt)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	return graphql.Time{Time: t}, nil
+}
+
+// EditedAt resolves the editedAt field.
+func (r *PersonResolver) EditedAt(ctx context.Context) (*graphql.Time, error) {
+	person, err := r.person.get(ctx)
+	if err != nil || person.EditedAt == "" {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, person.EditedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphql.Time{Time: t}, nil
+}
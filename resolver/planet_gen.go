@@ -0,0 +1,155 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// PlanetResolver resolves the Planet type.
+// The planet is only loaded once a field other than the ID is resolved.
+type PlanetResolver struct {
+	planet *lazy[swapi.Planet]
+}
+
+// NewPlanetArgs are the arguments for NewPlanet.
+// The planet is loaded from URL unless Planet is given.
+type NewPlanetArgs struct {
+	Planet swapi.Planet
+	URL    string
+}
+
+// NewPlanetsArgs are the arguments for NewPlanets.
+type NewPlanetsArgs struct {
+	Page swapi.PlanetPage
+	URLs []string
+}
+
+// NewPlanet creates a resolver for a single planet.
+func NewPlanet(ctx context.Context, args NewPlanetArgs) (*PlanetResolver, error) {
+	switch {
+	case args.Planet.URL != "":
+		return &PlanetResolver{planet: loaded(args.Planet.URL, args.Planet)}, nil
+	case args.URL != "":
+		return &PlanetResolver{planet: deferred(args.URL, loader.LoadPlanet)}, nil
+	default:
+		return nil, errors.UnableToResolve
+	}
+}
+
+// NewPlanets creates resolvers for a list of URLs followed by a search results page.
+//
+// Each resolver keeps the position of its URL. The resources are loaded lazily, so a resource which
+// fails to load only fails the fields resolved on it, and the errors carry the resource's index.
+func NewPlanets(ctx context.Context, args NewPlanetsArgs) (*[]*PlanetResolver, error) {
+	err := loader.PrimePlanets(ctx, args.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*PlanetResolver, 0, len(args.URLs)+len(args.Page.Planets))
+
+	for _, url := range args.URLs {
+		resolvers = append(resolvers, &PlanetResolver{planet: deferred(url, loader.LoadPlanet)})
+	}
+
+	for _, v := range args.Page.Planets {
+		resolvers = append(resolvers, &PlanetResolver{planet: loaded(v.URL, v)})
+	}
+
+	return &resolvers, nil
+}
+
+// field identifies one of this planet's attributes for data-quality warnings.
+func (r *PlanetResolver) field(name string) normalize.Field {
+	return normalize.Field{Type: "Planet", ID: string(r.ID()), Name: name}
+}
+
+// ID resolves the id field.
+func (r *PlanetResolver) ID() graphql.ID {
+	return extractID(r.planet.url)
+}
+
+// Name resolves the name field.
+func (r *PlanetResolver) Name(ctx context.Context) (string, error) {
+	planet, err := r.planet.get(ctx)
+	return planet.Name, err
+}
+
+// Climates resolves the climates field.
+func (r *PlanetResolver) Climates(ctx context.Context) ([]string, error) {
+	planet, err := r.planet.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(planet.Climate), nil
+}
+
+// Terrains resolves the terrains field.
+func (r *PlanetResolver) Terrains(ctx context.Context) ([]string, error) {
+	planet, err := r.planet.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(planet.Terrain), nil
+}
+
+// Residents resolves the residents field.
+func (r *PlanetResolver) Residents(ctx context.Context) (*[]*PersonResolver, error) {
+	planet, err := r.planet.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPeople(ctx, NewPeopleArgs{URLs: planet.ResidentURLs})
+}
+
+// Films resolves the films field.
+func (r *PlanetResolver) Films(ctx context.Context) (*[]*FilmResolver, error) {
+	planet, err := r.planet.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilms(ctx, NewFilmsArgs{URLs: planet.FilmURLs})
+}
+
+// CreatedAt resolves the createdAt field.
+func (r *PlanetResolver) CreatedAt(ctx context.Context) (graphql.Time, error) {
+	planet, err := r.planet.get(ctx)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, planet.CreatedAt)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	return graphql.Time{Time: t}, nil
+}
+
+// EditedAt resolves the editedAt field.
+func (r *PlanetResolver) EditedAt(ctx context.Context) (*graphql.Time, error) {
+	planet, err := r.planet.get(ctx)
+	if err != nil || planet.EditedAt == "" {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, planet.EditedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphql.Time{Time: t}, nil
+}
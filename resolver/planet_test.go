@@ -1,7 +1,34 @@
 package resolver_test
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/resolver"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
 
 func TestPlanetResolver(t *testing.T) {
+	ctx, report := normalize.Attach(context.Background())
+
+	r, err := resolver.NewPlanet(ctx, resolver.NewPlanetArgs{
+		Planet: swapi.Planet{Name: "Hoth", Population: "unknown", URL: "https://swapi.dev/api/planets/4/"},
+	})
+	require.NoError(t, err)
+
+	population, err := r.Population(ctx)
+	require.NoError(t, err)
+	require.Nil(t, population)
+
+	big, err := r.PopulationAsBigInt(ctx)
+	require.NoError(t, err)
+	require.Nil(t, big)
 
+	// Both fields hold the same value, so its warning is reported once.
+	require.Equal(t, []normalize.Warning{
+		{Type: "Planet", ID: "4", Field: "population", Value: "unknown", Message: "value is unknown"},
+	}, report.Warnings())
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 
 	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/schema"
 	"github.com/tonyghita/graphql-go-example/swapi"
 )
 
 // The QueryResolver is the entry point for all top-level read operations.
 type QueryResolver struct {
 	client *swapi.Client
+	// sdl is the SDL this API presents to a federation router.
+	sdl string
 }
 
 func NewRoot(client *swapi.Client) (*QueryResolver, error) {
@@ -17,7 +20,12 @@ func NewRoot(client *swapi.Client) (*QueryResolver, error) {
 		return nil, errors.UnableToResolve
 	}
 
-	return &QueryResolver{client: client}, nil
+	sdl, err := schema.Subgraph()
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryResolver{client: client, sdl: sdl}, nil
 }
 
 // FilmsQueryArgs are the arguments for the "films" query.
@@ -17,6 +17,6 @@ func TestResolversSatisfySchema(t *testing.T) {
 
 	rootResolver := &resolver.QueryResolver{}
 
-	_, err = graphql.ParseSchema(s, rootResolver)
+	_, err = graphql.ParseSchema(s, rootResolver, graphql.UseStringDescriptions())
 	require.NoError(t, err)
 }
@@ -0,0 +1,128 @@
+package resolver_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/resolver"
+	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// characters is the number of characters in the film served by fakeSWAPI.
+const characters = 20
+
+// fakeSWAPI serves a single film and its characters, counting the requests it receives.
+type fakeSWAPI struct {
+	calls   int64
+	missing string // The path of a person who is not found.
+}
+
+func (f *fakeSWAPI) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&f.calls, 1)
+
+	var v interface{}
+
+	switch path := r.URL.Path; {
+	case path == f.missing:
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"detail":"Not found"}`))}, nil
+	case strings.HasPrefix(path, "/api/films"):
+		film := swapi.Film{Title: "A New Hope", URL: "https://swapi.dev/api/films/1/"}
+		for i := 1; i <= characters; i++ {
+			film.CharacterURLs = append(film.CharacterURLs, fmt.Sprintf("https://swapi.dev/api/people/%d/", i))
+		}
+
+		v = swapi.FilmPage{Count: 1, Films: []swapi.Film{film}}
+	case strings.HasPrefix(path, "/api/people/"):
+		v = swapi.Person{Name: "Person " + path, URL: r.URL.String()}
+	default:
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(b)))}, nil
+}
+
+// execute runs the query against a schema backed by fakeSWAPI and returns the number of
+// upstream requests it made.
+func execute(tb testing.TB, s *graphql.Schema, api *fakeSWAPI, c *swapi.Client, query string) int64 {
+	tb.Helper()
+
+	before := atomic.LoadInt64(&api.calls)
+	ctx := loader.Initialize(c, loader.Settings{}).Attach(context.Background())
+
+	res := s.Exec(ctx, query, "", nil)
+	require.Empty(tb, res.Errors)
+
+	return atomic.LoadInt64(&api.calls) - before
+}
+
+func newSelectionSchema(tb testing.TB) (*graphql.Schema, *fakeSWAPI, *swapi.Client) {
+	tb.Helper()
+
+	api := &fakeSWAPI{}
+	c := swapi.NewClient(&http.Client{Transport: api})
+
+	root, err := resolver.NewRoot(c)
+	require.NoError(tb, err)
+
+	src, err := schema.String()
+	require.NoError(tb, err)
+
+	return graphql.MustParseSchema(src, root, graphql.UseStringDescriptions()), api, c
+}
+
+const (
+	idOnlyQuery = `{ films { characters { id __typename } } }`
+	namesQuery  = `{ films { characters { id name } } }`
+)
+
+func TestSelectionAwareResolution(t *testing.T) {
+	s, api, c := newSelectionSchema(t)
+
+	t.Run("ID only", func(t *testing.T) {
+		// Only the film search: every character ID is derived from its URL.
+		require.EqualValues(t, 1, execute(t, s, api, c, idOnlyQuery))
+	})
+
+	t.Run("Data fields", func(t *testing.T) {
+		require.EqualValues(t, 1+characters, execute(t, s, api, c, namesQuery))
+	})
+}
+
+func benchmarkSelection(b *testing.B, query string) {
+	s, api, c := newSelectionSchema(b)
+
+	var calls int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		calls += execute(b, s, api, c, query)
+	}
+
+	b.ReportMetric(float64(calls)/float64(b.N), "upstream-calls/op")
+}
+
+func BenchmarkSelectionIDOnly(b *testing.B) {
+	benchmarkSelection(b, idOnlyQuery)
+}
+
+func BenchmarkSelectionDataFields(b *testing.B) {
+	benchmarkSelection(b, namesQuery)
+}
@@ -0,0 +1,193 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// SpeciesResolver resolves the Species type.
+// The species is only loaded once a field other than the ID is resolved.
+type SpeciesResolver struct {
+	species *lazy[swapi.Species]
+}
+
+// NewSpeciesArgs are the arguments for NewSpecies.
+// The species is loaded from URL unless Species is given.
+type NewSpeciesArgs struct {
+	Species swapi.Species
+	URL     string
+}
+
+// NewSpeciesListArgs are the arguments for NewSpeciesList.
+type NewSpeciesListArgs struct {
+	Page swapi.SpeciesPage
+	URLs []string
+}
+
+// NewSpecies creates a resolver for a single species.
+func NewSpecies(ctx context.Context, args NewSpeciesArgs) (*SpeciesResolver, error) {
+	switch {
+	case args.Species.URL != "":
+		return &SpeciesResolver{species: loaded(args.Species.URL, args.Species)}, nil
+	case args.URL != "":
+		return &SpeciesResolver{species: deferred(args.URL, loader.LoadSpecies)}, nil
+	default:
+		return nil, errors.UnableToResolve
+	}
+}
+
+// NewSpeciesList creates resolvers for a list of URLs followed by a search results page.
+//
+// Each resolver keeps the position of its URL. The resources are loaded lazily, so a resource which
+// fails to load only fails the fields resolved on it, and the errors carry the resource's index.
+func NewSpeciesList(ctx context.Context, args NewSpeciesListArgs) (*[]*SpeciesResolver, error) {
+	err := loader.PrimeManySpecies(ctx, args.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*SpeciesResolver, 0, len(args.URLs)+len(args.Page.Species))
+
+	for _, url := range args.URLs {
+		resolvers = append(resolvers, &SpeciesResolver{species: deferred(url, loader.LoadSpecies)})
+	}
+
+	for _, v := range args.Page.Species {
+		resolvers = append(resolvers, &SpeciesResolver{species: loaded(v.URL, v)})
+	}
+
+	return &resolvers, nil
+}
+
+// field identifies one of this species's attributes for data-quality warnings.
+func (r *SpeciesResolver) field(name string) normalize.Field {
+	return normalize.Field{Type: "Species", ID: string(r.ID()), Name: name}
+}
+
+// ID resolves the id field.
+func (r *SpeciesResolver) ID() graphql.ID {
+	return extractID(r.species.url)
+}
+
+// Name resolves the name field.
+func (r *SpeciesResolver) Name(ctx context.Context) (string, error) {
+	species, err := r.species.get(ctx)
+	return species.Name, err
+}
+
+// Classification resolves the classification field.
+func (r *SpeciesResolver) Classification(ctx context.Context) (string, error) {
+	species, err := r.species.get(ctx)
+	return species.Classification, err
+}
+
+// Designation resolves the designation field.
+func (r *SpeciesResolver) Designation(ctx context.Context) (string, error) {
+	species, err := r.species.get(ctx)
+	return species.Designation, err
+}
+
+// EyeColors resolves the eyeColors field.
+func (r *SpeciesResolver) EyeColors(ctx context.Context) ([]string, error) {
+	species, err := r.species.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(species.EyeColors), nil
+}
+
+// HairColors resolves the hairColors field.
+func (r *SpeciesResolver) HairColors(ctx context.Context) ([]string, error) {
+	species, err := r.species.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(species.HairColors), nil
+}
+
+// SkinColors resolves the skinColors field.
+func (r *SpeciesResolver) SkinColors(ctx context.Context) ([]string, error) {
+	species, err := r.species.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(species.SkinColors), nil
+}
+
+// Language resolves the language field.
+func (r *SpeciesResolver) Language(ctx context.Context) (string, error) {
+	species, err := r.species.get(ctx)
+	return species.Language, err
+}
+
+// Homeworld resolves the homeworld field.
+func (r *SpeciesResolver) Homeworld(ctx context.Context) (*PlanetResolver, error) {
+	species, err := r.species.get(ctx)
+	if err != nil || species.HomeworldURL == "" {
+		return nil, err
+	}
+
+	return NewPlanet(ctx, NewPlanetArgs{URL: species.HomeworldURL})
+}
+
+// Characters resolves the characters field.
+func (r *SpeciesResolver) Characters(ctx context.Context) (*[]*PersonResolver, error) {
+	species, err := r.species.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPeople(ctx, NewPeopleArgs{URLs: species.PeopleURLs})
+}
+
+// Films resolves the films field.
+func (r *SpeciesResolver) Films(ctx context.Context) (*[]*FilmResolver, error) {
+	species, err := r.species.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilms(ctx, NewFilmsArgs{URLs: species.FilmURLs})
+}
+
+// CreatedAt resolves the createdAt field.
+func (r *SpeciesResolver) CreatedAt(ctx context.Context) (graphql.Time, error) {
+	species, err := r.species.get(ctx)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, species.CreatedAt)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	return graphql.Time{Time: t}, nil
+}
+
+// EditedAt resolves the editedAt field.
+func (r *SpeciesResolver) EditedAt(ctx context.Context) (*graphql.Time, error) {
+	species, err := r.species.get(ctx)
+	if err != nil || species.EditedAt == "" {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, species.EditedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphql.Time{Time: t}, nil
+}
@@ -2,233 +2,137 @@ package resolver
 
 import (
 	"context"
-	"strconv"
-	"strings"
-	"time"
+	"math"
 
-	"github.com/tonyghita/graphql-go-example/errors"
-	"github.com/tonyghita/graphql-go-example/loader"
-	"github.com/tonyghita/graphql-go-example/swapi"
-
-	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/units"
 )
 
-// The StarshipResolver contains the data required to resolve the Starship type.
-type StarshipResolver struct {
-	ship swapi.Starship
-}
-
-type NewStarshipArgs struct {
-	Ship swapi.Starship
-	URL  string
-}
-
-type NewStarshipsArgs struct {
-	Page swapi.StarshipPage
-	URLs []string
-}
-
-func NewStarship(ctx context.Context, args NewStarshipArgs) (*StarshipResolver, error) {
-	var ship swapi.Starship
-	var err error
-
-	switch {
-	case args.Ship.URL != "":
-		ship = args.Ship
-	case args.URL != "":
-		ship, err = loader.LoadStarship(ctx, args.URL)
-	default:
-		err = errors.UnableToResolve
+// Cost resolves ...
+func (r *StarshipResolver) Cost(ctx context.Context) (*Long, error) {
+	if err := auth.Authorize(ctx, "Starship.cost"); err != nil {
+		return nil, err
 	}
 
+	ship, err := r.ship.get(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &StarshipResolver{ship: ship}, nil
+	return nullableLong(normalize.Int64(ctx, r.field("cost"), ship.CostInCredits)), nil
 }
 
-func NewStarships(ctx context.Context, args NewStarshipsArgs) (*[]*StarshipResolver, error) {
-	err := loader.PrimeStarships(ctx, args.Page)
-	if err != nil {
+// CostAsBigInt resolves ...
+// Its warnings name the cost field, which holds the same value, so selecting both fields reports
+// each warning once.
+func (r *StarshipResolver) CostAsBigInt(ctx context.Context) (*BigInt, error) {
+	if err := auth.Authorize(ctx, "Starship.costAsBigInt"); err != nil {
 		return nil, err
 	}
 
-	results, err := loader.LoadStarships(ctx, append(args.URLs, args.Page.URLs()...))
+	ship, err := r.ship.get(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var ships = results.WithoutErrors()
-	var resolvers = make([]*StarshipResolver, 0, len(ships))
-	var errs errors.Errors
-
-	for i, ship := range ships {
-		resolver, err := NewStarship(ctx, NewStarshipArgs{Ship: ship})
-		if err != nil {
-			errs = append(errs, errors.WithIndex(err, i))
-		}
-
-		resolvers = append(resolvers, resolver)
-	}
-
-	return &resolvers, errs.Err()
-}
-
-// ID resolves ...
-func (r *StarshipResolver) ID() graphql.ID {
-	return extractID(r.ship.URL)
-}
-
-// Name resolves ...
-func (r *StarshipResolver) Name() string {
-	return r.ship.Name
-}
-
-// Model resolves ...
-func (r *StarshipResolver) Model() string {
-	return r.ship.Model
-}
-
-// Class resolves ...
-func (r *StarshipResolver) Class() string {
-	return r.ship.StarshipClass
+	return nullableBigInt(normalize.BigInt(ctx, r.field("cost"), ship.CostInCredits)), nil
 }
 
-// Manufacturers resolves ...
-func (r *StarshipResolver) Manufacturers() []string {
-	return strings.Split(r.ship.Manufacturer, ",")
-}
-
-// Cost resolves ...
-func (r *StarshipResolver) Cost() (int32, error) {
-	c, err := strconv.ParseInt(r.ship.CostInCredits, 10, 32)
+// Length resolves ...
+func (r *StarshipResolver) Length(ctx context.Context, args UnitArgs) (*float64, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return 0, errors.UnableToResolve
+		return nil, err
 	}
 
-	return int32(c), nil
+	return convert(args, normalize.Float(ctx, r.field("length"), ship.Length), units.Meter)
 }
 
-// Length resolves ...
-func (r *StarshipResolver) Length(args LengthUnitArgs) (float64, error) {
-	unit, err := ToLengthUnit(args.Unit)
-	if err != nil {
-		return 0.0, err
-	}
-
-	l, err := strconv.ParseFloat(r.ship.Length, 64)
+// CrewSize resolves ...
+func (r *StarshipResolver) CrewSize(ctx context.Context) (*int32, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return 0.0, err
+		return nil, err
 	}
 
-	return ConvertLength(l, Meter, unit), nil
+	return normalize.Int32(ctx, r.field("crewSize"), ship.Crew), nil
 }
 
-// CrewSize resolves ...
-func (r *StarshipResolver) CrewSize() (int32, error) {
-	s, err := strconv.ParseInt(r.ship.Crew, 10, 32)
+// Passengers resolves ...
+func (r *StarshipResolver) Passengers(ctx context.Context) (*Long, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return 0, errors.UnableToResolve
+		return nil, err
 	}
 
-	return int32(s), nil
+	return nullableLong(normalize.Int64(ctx, r.field("passengers"), ship.Passengers)), nil
 }
 
 // PassengerCapacity resolves ...
-func (r *StarshipResolver) PassengerCapacity() (int32, error) {
-	c, err := strconv.ParseInt(r.ship.CargoCapacity, 10, 32)
+func (r *StarshipResolver) PassengerCapacity(ctx context.Context) (*int32, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return 0, errors.UnableToResolve
+		return nil, err
 	}
 
-	return int32(c), nil
+	return normalize.Int32(ctx, r.field("passengerCapacity"), ship.Passengers), nil
 }
 
 // MaxAtmosphericSpeed resolves ...
-func (r *StarshipResolver) MaxAtmosphericSpeed() (*int32, error) {
-	if r.ship.MaxAtmospheringSpeed == "" {
-		return nil, nil
+func (r *StarshipResolver) MaxAtmosphericSpeed(ctx context.Context, args UnitArgs) (*int32, error) {
+	ship, err := r.ship.get(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	s, err := strconv.ParseInt(r.ship.MaxAtmospheringSpeed, 10, 32)
-	if err != nil {
-		return nil, errors.UnableToResolve
+	v, err := convert(args, normalize.Float(ctx, r.field("maxAtmosphericSpeed"), ship.MaxAtmospheringSpeed), units.KilometersPerHour)
+	if err != nil || v == nil {
+		return nil, err
 	}
 
-	i := int32(s)
+	// The field predates units and remains an Int, so the converted speed is rounded.
+	i := int32(math.Round(*v))
+
 	return &i, nil
 }
 
 // HyperdriveRating resolves ...
-func (r *StarshipResolver) HyperdriveRating() (*float64, error) {
-	if r.ship.HyperdriveRating == "" {
-		return nil, nil
-	}
-
-	f, err := strconv.ParseFloat(r.ship.HyperdriveRating, 64)
+func (r *StarshipResolver) HyperdriveRating(ctx context.Context) (*float64, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return nil, errors.UnableToResolve
+		return nil, err
 	}
 
-	return &f, nil
+	return normalize.Float(ctx, r.field("hyperdriveRating"), ship.HyperdriveRating), nil
 }
 
 // MaxMegalightsPerHour ...
-func (r *StarshipResolver) MaxMegalightsPerHour() (int32, error) {
-	i, err := strconv.ParseInt(r.ship.MGLT, 10, 32)
+func (r *StarshipResolver) MaxMegalightsPerHour(ctx context.Context) (*int32, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return 0, errors.UnableToResolve
+		return nil, err
 	}
 
-	return int32(i), nil
+	return normalize.Int32(ctx, r.field("maxMegalightsPerHour"), ship.MGLT), nil
 }
 
 // CargoCapacity resolves ...
-func (r *StarshipResolver) CargoCapacity(args LengthUnitArgs) (float64, error) {
-	f, err := strconv.ParseFloat(r.ship.CargoCapacity, 64)
+func (r *StarshipResolver) CargoCapacity(ctx context.Context, args UnitArgs) (*float64, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return 0.0, errors.UnableToResolve
-	}
-
-	return f, nil
-}
-
-// ConsumablesDuration resolves ...
-func (r *StarshipResolver) ConsumablesDuration() string {
-	return r.ship.Consumables
-}
-
-// Films resolves ...
-func (r *StarshipResolver) Films(ctx context.Context) (*[]*FilmResolver, error) {
-	return NewFilms(ctx, NewFilmsArgs{URLs: r.ship.FilmURLs})
-}
-
-// Pilots resolves ...
-func (r *StarshipResolver) Pilots(ctx context.Context) (*[]*PersonResolver, error) {
-	return NewPeople(ctx, NewPeopleArgs{URLs: r.ship.PilotURLs})
-}
-
-// CreatedAt resolves ...
-func (r *StarshipResolver) CreatedAt() (graphql.Time, error) {
-	t, err := time.Parse(time.RFC3339, r.ship.CreatedAt)
-	if err != nil {
-		return graphql.Time{}, errors.UnableToResolve
+		return nil, err
 	}
 
-	return graphql.Time{Time: t}, nil
+	return convert(args, normalize.Float(ctx, r.field("cargoCapacity"), ship.CargoCapacity), units.Kilogram)
 }
 
-// EditedAt resolves ...
-func (r *StarshipResolver) EditedAt() (*graphql.Time, error) {
-	if r.ship.EditedAt == "" {
-		return nil, nil
-	}
-
-	t, err := time.Parse(time.RFC3339, r.ship.EditedAt)
+// Consumables resolves ...
+func (r *StarshipResolver) Consumables(ctx context.Context) (*DurationResolver, error) {
+	ship, err := r.ship.get(ctx)
 	if err != nil {
-		return nil, errors.UnableToResolve
+		return nil, err
 	}
 
-	return &graphql.Time{Time: t}, nil
+	return newDuration(r.field("consumables"), ship.Consumables), nil
 }
@@ -0,0 +1,163 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// StarshipResolver resolves the Starship type.
+// The starship is only loaded once a field other than the ID is resolved.
+type StarshipResolver struct {
+	ship *lazy[swapi.Starship]
+}
+
+// NewStarshipArgs are the arguments for NewStarship.
+// The starship is loaded from URL unless Ship is given.
+type NewStarshipArgs struct {
+	Ship swapi.Starship
+	URL  string
+}
+
+// NewStarshipsArgs are the arguments for NewStarships.
+type NewStarshipsArgs struct {
+	Page swapi.StarshipPage
+	URLs []string
+}
+
+// NewStarship creates a resolver for a single starship.
+func NewStarship(ctx context.Context, args NewStarshipArgs) (*StarshipResolver, error) {
+	switch {
+	case args.Ship.URL != "":
+		return &StarshipResolver{ship: loaded(args.Ship.URL, args.Ship)}, nil
+	case args.URL != "":
+		return &StarshipResolver{ship: deferred(args.URL, loader.LoadStarship)}, nil
+	default:
+		return nil, errors.UnableToResolve
+	}
+}
+
+// NewStarships creates resolvers for a list of URLs followed by a search results page.
+//
+// Each resolver keeps the position of its URL. The resources are loaded lazily, so a resource which
+// fails to load only fails the fields resolved on it, and the errors carry the resource's index.
+func NewStarships(ctx context.Context, args NewStarshipsArgs) (*[]*StarshipResolver, error) {
+	err := loader.PrimeStarships(ctx, args.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*StarshipResolver, 0, len(args.URLs)+len(args.Page.Starships))
+
+	for _, url := range args.URLs {
+		resolvers = append(resolvers, &StarshipResolver{ship: deferred(url, loader.LoadStarship)})
+	}
+
+	for _, v := range args.Page.Starships {
+		resolvers = append(resolvers, &StarshipResolver{ship: loaded(v.URL, v)})
+	}
+
+	return &resolvers, nil
+}
+
+// field identifies one of this starship's attributes for data-quality warnings.
+func (r *StarshipResolver) field(name string) normalize.Field {
+	return normalize.Field{Type: "Starship", ID: string(r.ID()), Name: name}
+}
+
+// ID resolves the id field.
+func (r *StarshipResolver) ID() graphql.ID {
+	return extractID(r.ship.url)
+}
+
+// Name resolves the name field.
+func (r *StarshipResolver) Name(ctx context.Context) (string, error) {
+	ship, err := r.ship.get(ctx)
+	return ship.Name, err
+}
+
+// Model resolves the model field.
+func (r *StarshipResolver) Model(ctx context.Context) (string, error) {
+	ship, err := r.ship.get(ctx)
+	return ship.Model, err
+}
+
+// Class resolves the class field.
+func (r *StarshipResolver) Class(ctx context.Context) (string, error) {
+	ship, err := r.ship.get(ctx)
+	return ship.StarshipClass, err
+}
+
+// Manufacturers resolves the manufacturers field.
+func (r *StarshipResolver) Manufacturers(ctx context.Context) ([]string, error) {
+	ship, err := r.ship.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(ship.Manufacturer), nil
+}
+
+// ConsumablesDuration resolves the consumablesDuration field.
+func (r *StarshipResolver) ConsumablesDuration(ctx context.Context) (string, error) {
+	ship, err := r.ship.get(ctx)
+	return ship.Consumables, err
+}
+
+// Films resolves the films field.
+func (r *StarshipResolver) Films(ctx context.Context) (*[]*FilmResolver, error) {
+	ship, err := r.ship.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilms(ctx, NewFilmsArgs{URLs: ship.FilmURLs})
+}
+
+// Pilots resolves the pilots field.
+func (r *StarshipResolver) Pilots(ctx context.Context) (*[]*PersonResolver, error) {
+	ship, err := r.ship.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPeople(ctx, NewPeopleArgs{URLs: ship.PilotURLs})
+}
+
+// CreatedAt resolves the createdAt field.
+func (r *StarshipResolver) CreatedAt(ctx context.Context) (graphql.Time, error) {
+	ship, err := r.ship.get(ctx)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, ship.CreatedAt)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	return graphql.Time{Time: t}, nil
+}
+
+// EditedAt resolves the editedAt field.
+func (r *StarshipResolver) EditedAt(ctx context.Context) (*graphql.Time, error) {
+	ship, err := r.ship.get(ctx)
+	if err != nil || ship.EditedAt == "" {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, ship.EditedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphql.Time{Time: t}, nil
+}
@@ -0,0 +1,29 @@
+package resolver
+
+import (
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/units"
+)
+
+// UnitArgs are the arguments for fields that can be expressed in any unit of their dimension, such
+// as a LengthUnit.
+type UnitArgs struct {
+	Unit string
+}
+
+// convert converts an optional value measured in the unit from into the requested unit. The
+// requested unit is checked even when the value is unknown.
+func convert[U units.Unit](args UnitArgs, v *float64, from U) (*float64, error) {
+	to, err := units.Parse[U](args.Unit)
+	if err != nil {
+		return nil, errors.WithCode(err, errors.BadUserInput)
+	}
+
+	if v == nil {
+		return nil, nil
+	}
+
+	c := units.Convert(*v, from, to)
+
+	return &c, nil
+}
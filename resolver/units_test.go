@@ -0,0 +1,27 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/units"
+)
+
+func TestConvert(t *testing.T) {
+	v := 1000.0
+
+	c, err := convert(UnitArgs{Unit: "MILES_PER_HOUR"}, &v, units.KilometersPerHour)
+	require.NoError(t, err)
+	require.InDelta(t, 621.371, *c, 1e-3)
+
+	c, err = convert(UnitArgs{Unit: "KNOT"}, nil, units.KilometersPerHour)
+	require.NoError(t, err)
+	require.Nil(t, c)
+
+	// The unit is checked even when the value is unknown.
+	_, err = convert(UnitArgs{Unit: "FURLONG"}, nil, units.Meter)
+	require.EqualError(t, err, "unknown LengthUnit: FURLONG")
+	require.Equal(t, errors.BadUserInput, errors.CodeOf(err))
+}
@@ -0,0 +1,163 @@
+// Code generated by swapigen. DO NOT EDIT.
+
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/tonyghita/graphql-go-example/errors"
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/normalize"
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+// VehicleResolver resolves the Vehicle type.
+// The vehicle is only loaded once a field other than the ID is resolved.
+type VehicleResolver struct {
+	vehicle *lazy[swapi.Vehicle]
+}
+
+// NewVehicleArgs are the arguments for NewVehicle.
+// The vehicle is loaded from URL unless Vehicle is given.
+type NewVehicleArgs struct {
+	Vehicle swapi.Vehicle
+	URL     string
+}
+
+// NewVehiclesArgs are the arguments for NewVehicles.
+type NewVehiclesArgs struct {
+	Page swapi.VehiclePage
+	URLs []string
+}
+
+// NewVehicle creates a resolver for a single vehicle.
+func NewVehicle(ctx context.Context, args NewVehicleArgs) (*VehicleResolver, error) {
+	switch {
+	case args.Vehicle.URL != "":
+		return &VehicleResolver{vehicle: loaded(args.Vehicle.URL, args.Vehicle)}, nil
+	case args.URL != "":
+		return &VehicleResolver{vehicle: deferred(args.URL, loader.LoadVehicle)}, nil
+	default:
+		return nil, errors.UnableToResolve
+	}
+}
+
+// NewVehicles creates resolvers for a list of URLs followed by a search results page.
+//
+// Each resolver keeps the position of its URL. The resources are loaded lazily, so a resource which
+// fails to load only fails the fields resolved on it, and the errors carry the resource's index.
+func NewVehicles(ctx context.Context, args NewVehiclesArgs) (*[]*VehicleResolver, error) {
+	err := loader.PrimeVehicles(ctx, args.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*VehicleResolver, 0, len(args.URLs)+len(args.Page.Vehicles))
+
+	for _, url := range args.URLs {
+		resolvers = append(resolvers, &VehicleResolver{vehicle: deferred(url, loader.LoadVehicle)})
+	}
+
+	for _, v := range args.Page.Vehicles {
+		resolvers = append(resolvers, &VehicleResolver{vehicle: loaded(v.URL, v)})
+	}
+
+	return &resolvers, nil
+}
+
+// field identifies one of this vehicle's attributes for data-quality warnings.
+func (r *VehicleResolver) field(name string) normalize.Field {
+	return normalize.Field{Type: "Vehicle", ID: string(r.ID()), Name: name}
+}
+
+// ID resolves the id field.
+func (r *VehicleResolver) ID() graphql.ID {
+	return extractID(r.vehicle.url)
+}
+
+// Name resolves the name field.
+func (r *VehicleResolver) Name(ctx context.Context) (string, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	return vehicle.Name, err
+}
+
+// Model resolves the model field.
+func (r *VehicleResolver) Model(ctx context.Context) (string, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	return vehicle.Model, err
+}
+
+// Class resolves the class field.
+func (r *VehicleResolver) Class(ctx context.Context) (string, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	return vehicle.VehicleClass, err
+}
+
+// Manufacturers resolves the manufacturers field.
+func (r *VehicleResolver) Manufacturers(ctx context.Context) ([]string, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(vehicle.Manufacturer), nil
+}
+
+// ConsumablesDuration resolves the consumablesDuration field.
+func (r *VehicleResolver) ConsumablesDuration(ctx context.Context) (string, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	return vehicle.Consumables, err
+}
+
+// Films resolves the films field.
+func (r *VehicleResolver) Films(ctx context.Context) (*[]*FilmResolver, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilms(ctx, NewFilmsArgs{URLs: vehicle.FilmURLs})
+}
+
+// Pilots resolves the pilots field.
+func (r *VehicleResolver) Pilots(ctx context.Context) (*[]*PersonResolver, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPeople(ctx, NewPeopleArgs{URLs: vehicle.PilotURLs})
+}
+
+// CreatedAt resolves the createdAt field.
+func (r *VehicleResolver) CreatedAt(ctx context.Context) (graphql.Time, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, vehicle.CreatedAt)
+	if err != nil {
+		return graphql.Time{}, err
+	}
+
+	return graphql.Time{Time: t}, nil
+}
+
+// EditedAt resolves the editedAt field.
+func (r *VehicleResolver) EditedAt(ctx context.Context) (*graphql.Time, error) {
+	vehicle, err := r.vehicle.get(ctx)
+	if err != nil || vehicle.EditedAt == "" {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, vehicle.EditedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphql.Time{Time: t}, nil
+}
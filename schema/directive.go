@@ -0,0 +1,299 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tonyghita/graphql-go-example/language"
+)
+
+// A Directive is a directive applied to a type, field or enum value in the SDL.
+//
+// graphql-go only acts on the directives defined by the GraphQL specification, so the directives
+// this API defines for itself are read from the SDL with ParseDirectives.
+type Directive struct {
+	Name string
+	// Args maps argument names to their values as written in the SDL.
+	// String values are unquoted; lists and objects are kept as raw text.
+	Args map[string]string
+}
+
+// Directives maps schema coordinates to the directives applied at them.
+// A coordinate is either a type name, such as "Film", or a type and member name separated by a
+// dot, such as "Film.title" or "LengthUnit.METER".
+type Directives map[string][]Directive
+
+// Find returns the directive with the given name applied at the coordinate.
+func (d Directives) Find(coordinate, name string) (Directive, bool) {
+	for _, dir := range d[coordinate] {
+		if dir.Name == name {
+			return dir, true
+		}
+	}
+
+	return Directive{}, false
+}
+
+// ParseDirectives reads every directive applied to types, fields and enum values in the SDL.
+func ParseDirectives(sdl string) (Directives, error) {
+	p := &sdlParser{lexer: language.NewLexer(sdl)}
+	p.next()
+
+	d := make(Directives)
+
+	for p.tok.Kind != language.EOF {
+		if p.err != nil {
+			return nil, p.err
+		}
+
+		if p.tok.Kind == language.String { // A description.
+			p.next()
+			continue
+		}
+
+		if p.tok.Kind != language.Name {
+			return nil, p.errorf("unexpected %q", p.tok.Text)
+		}
+
+		switch keyword := p.tok.Text; keyword {
+		case "extend":
+			p.next()
+		case "type", "interface", "input", "enum", "union", "scalar":
+			p.next()
+			p.definition(d, keyword)
+		case "directive":
+			p.directiveDefinition()
+		case "schema":
+			p.next()
+			p.directives()
+			p.skipBalanced("{", "}")
+		default:
+			return nil, p.errorf("unexpected %q", keyword)
+		}
+	}
+
+	return d, p.err
+}
+
+type sdlParser struct {
+	lexer *language.Lexer
+	tok   language.Token
+	err   error
+}
+
+func (p *sdlParser) next() {
+	if p.err != nil {
+		p.tok = language.Token{Kind: language.EOF}
+		return
+	}
+
+	p.tok, p.err = p.lexer.Next()
+}
+
+func (p *sdlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: "+format, append([]interface{}{p.tok.Line}, args...)...)
+}
+
+func (p *sdlParser) is(punct string) bool {
+	return p.tok.Kind == language.Punct && p.tok.Text == punct
+}
+
+func (p *sdlParser) expect(punct string) {
+	if !p.is(punct) && p.err == nil {
+		p.err = p.errorf("expected %q, got %q", punct, p.tok.Text)
+	}
+
+	p.next()
+}
+
+func (p *sdlParser) name() string {
+	if p.tok.Kind != language.Name && p.err == nil {
+		p.err = p.errorf("expected a name, got %q", p.tok.Text)
+	}
+
+	n := p.tok.Text
+	p.next()
+
+	return n
+}
+
+// definition reads a type definition following its keyword.
+func (p *sdlParser) definition(d Directives, keyword string) {
+	typ := p.name()
+
+	if p.tok.Kind == language.Name && p.tok.Text == "implements" {
+		p.next()
+
+		for p.tok.Kind == language.Name || p.is("&") {
+			p.next()
+		}
+	}
+
+	add(d, typ, p.directives())
+
+	if p.is("=") { // Union members.
+		p.next()
+
+		for p.tok.Kind == language.Name || p.is("|") {
+			p.next()
+		}
+	}
+
+	if !p.is("{") {
+		return
+	}
+
+	p.next()
+
+	for !p.is("}") && p.tok.Kind != language.EOF && p.err == nil {
+		if p.tok.Kind == language.String {
+			p.next()
+			continue
+		}
+
+		member := p.name()
+
+		if p.is("(") {
+			p.skipBalanced("(", ")")
+		}
+
+		if keyword != "enum" {
+			p.expect(":")
+			p.typeRef()
+
+			if p.is("=") {
+				p.next()
+				p.value()
+			}
+		}
+
+		add(d, typ+"."+member, p.directives())
+	}
+
+	p.expect("}")
+}
+
+// directiveDefinition skips a directive definition, which ends with its list of locations.
+func (p *sdlParser) directiveDefinition() {
+	p.next()
+	p.expect("@")
+	p.name()
+
+	if p.is("(") {
+		p.skipBalanced("(", ")")
+	}
+
+	if p.tok.Kind == language.Name && p.tok.Text == "repeatable" {
+		p.next()
+	}
+
+	if p.tok.Kind != language.Name || p.tok.Text != "on" {
+		p.err = p.errorf("expected \"on\", got %q", p.tok.Text)
+		return
+	}
+
+	p.next()
+
+	for p.tok.Kind == language.Name && strings.ToUpper(p.tok.Text) == p.tok.Text || p.is("|") {
+		p.next()
+	}
+}
+
+func (p *sdlParser) typeRef() {
+	if p.is("[") {
+		p.next()
+		p.typeRef()
+		p.expect("]")
+	} else {
+		p.name()
+	}
+
+	if p.is("!") {
+		p.next()
+	}
+}
+
+func (p *sdlParser) directives() []Directive {
+	var dirs []Directive
+
+	for p.is("@") && p.err == nil {
+		p.next()
+
+		dir := Directive{Name: p.name(), Args: map[string]string{}}
+
+		if p.is("(") {
+			p.next()
+
+			for !p.is(")") && p.tok.Kind != language.EOF && p.err == nil {
+				arg := p.name()
+				p.expect(":")
+				dir.Args[arg] = p.value()
+			}
+
+			p.expect(")")
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
+// value reads a value, returning scalars as written (with strings unquoted) and lists and objects
+// as raw text.
+func (p *sdlParser) value() string {
+	switch {
+	case p.is("["):
+		return p.skipBalanced("[", "]")
+	case p.is("{"):
+		return p.skipBalanced("{", "}")
+	case p.is("$"):
+		p.next()
+		return "$" + p.name()
+	}
+
+	v := p.tok.Text
+	p.next()
+
+	return v
+}
+
+// skipBalanced skips from an opening token to its matching closing token, returning the text of
+// the tokens in between.
+func (p *sdlParser) skipBalanced(open, close string) string {
+	var b strings.Builder
+
+	depth := 0
+
+	for p.tok.Kind != language.EOF && p.err == nil {
+		switch {
+		case p.is(open):
+			depth++
+		case p.is(close):
+			depth--
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(p.tok.Text)
+		p.next()
+
+		if depth == 0 {
+			break
+		}
+	}
+
+	s := b.String()
+	s = strings.TrimPrefix(s, open)
+	s = strings.TrimSuffix(s, close)
+
+	return strings.TrimSpace(s)
+}
+
+func add(d Directives, coordinate string, dirs []Directive) {
+	if len(dirs) > 0 {
+		d[coordinate] = append(d[coordinate], dirs...)
+	}
+}
@@ -33,6 +33,29 @@ var content embed.FS
 // If this method complains about not finding functions AssetNames() or MustAsset(),
 // run `go generate` against this package to generate the functions.
 func String() (string, error) {
+	return concat(func(string) bool { return true })
+}
+
+// federationFile defines the fields and types Apollo Federation requires of a subgraph.
+const federationFile = "federation.graphql"
+
+// federationLink imports the Federation directives the SDL uses.
+const federationLink = `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key", "@shareable", "@external"])`
+
+// Subgraph returns the SDL this API presents to a federation router as a subgraph: the schema
+// without the definitions of federation.graphql, which the router supplies itself, and with a link
+// to the Federation specification in their place.
+func Subgraph() (string, error) {
+	s, err := concat(func(path string) bool { return path != federationFile })
+	if err != nil {
+		return "", err
+	}
+
+	return federationLink + "\n\n" + s, nil
+}
+
+// concat concatenates the .graphql schema files for which include returns true.
+func concat(include func(path string) bool) (string, error) {
 	var buf bytes.Buffer
 
 	fn := func(path string, d fs.DirEntry, err error) error {
@@ -41,7 +64,7 @@ func String() (string, error) {
 		}
 
 		// Only add files with the .graphql extension.
-		if !strings.HasSuffix(path, ".graphql") {
+		if !strings.HasSuffix(path, ".graphql") || !include(path) {
 			return nil
 		}
 
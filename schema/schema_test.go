@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/schemalint"
 )
 
 func TestString(t *testing.T) {
@@ -13,3 +14,68 @@ func TestString(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, s)
 }
+
+func TestParseDirectives(t *testing.T) {
+	d, err := schema.ParseDirectives(`
+		directive @tag(name: String!, values: [Int] = [1]) repeatable on OBJECT | FIELD_DEFINITION
+
+		"""
+		A type.
+		"""
+		type Thing implements Node & Named @tag(name: "thing") @tag(name: "other") {
+			"The ID."
+			id: ID!
+			related(first: Int = 10, after: String): [Thing!]! @tag(name: "related", values: [1, 2])
+			legacy: String @deprecated(reason: "Use id.")
+		}
+
+		enum Color { RED @deprecated GREEN }
+
+		extend type Query { things: [Thing] @tag(name: "root") }
+	`)
+	require.NoError(t, err)
+
+	require.Len(t, d["Thing"], 2)
+
+	dir, ok := d.Find("Thing.related", "tag")
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"name": "related", "values": "1 2"}, dir.Args)
+
+	dir, ok = d.Find("Thing.legacy", "deprecated")
+	require.True(t, ok)
+	require.Equal(t, "Use id.", dir.Args["reason"])
+
+	_, ok = d.Find("Color.RED", "deprecated")
+	require.True(t, ok)
+
+	_, ok = d.Find("Query.things", "tag")
+	require.True(t, ok)
+
+	_, ok = d.Find("Thing.id", "tag")
+	require.False(t, ok)
+}
+
+func TestParseDirectivesSchema(t *testing.T) {
+	s, err := schema.String()
+	require.NoError(t, err)
+
+	d, err := schema.ParseDirectives(s)
+	require.NoError(t, err)
+
+	dir, ok := d.Find("Film", "cacheControl")
+	require.True(t, ok)
+	require.Equal(t, "86400", dir.Args["maxAge"])
+}
+
+// TestLint keeps the schema documented and consistent; see package schemalint for the rules.
+func TestLint(t *testing.T) {
+	s, err := schema.String()
+	require.NoError(t, err)
+
+	problems, err := schemalint.Lint(s)
+	require.NoError(t, err)
+
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/schemadiff"
+	"github.com/tonyghita/graphql-go-example/schemalint"
+)
+
+const schemaUsage = `usage: graphql-go-example schema <command> [arguments]
+
+Commands:
+  print            print the schema in SDL
+  introspect       print the result of an introspection query as JSON
+  diff <baseline>  compare a baseline schema, in SDL or introspection JSON, with this schema;
+                   exits with status 1 when there are breaking changes
+  lint             check the schema's documentation, naming, units and list nullability;
+                   exits with status 1 when there are problems
+`
+
+// schemaCommand runs the schema subcommand and returns the exit status.
+func schemaCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprint(stderr, schemaUsage) }
+
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	sdl, err := schema.String()
+	if err != nil {
+		fmt.Fprintf(stderr, "reading embedded schema contents: %s\n", err)
+		return 2
+	}
+
+	switch cmd := fs.Arg(0); {
+	case cmd == "print" && fs.NArg() == 1:
+		fmt.Fprint(stdout, sdl)
+	case cmd == "introspect" && fs.NArg() == 1:
+		s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+		if err != nil {
+			fmt.Fprintf(stderr, "parsing schema: %s\n", err)
+			return 2
+		}
+
+		b, err := s.ToJSON()
+		if err != nil {
+			fmt.Fprintf(stderr, "introspecting schema: %s\n", err)
+			return 2
+		}
+
+		fmt.Fprintf(stdout, "%s\n", b)
+	case cmd == "diff" && fs.NArg() == 2:
+		return schemaDiff(fs.Arg(1), sdl, stdout, stderr)
+	case cmd == "lint" && fs.NArg() == 1:
+		return schemaLint(sdl, stdout, stderr)
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	return 0
+}
+
+func schemaDiff(baseline, sdl string, stdout, stderr io.Writer) int {
+	b, err := os.ReadFile(baseline)
+	if err != nil {
+		fmt.Fprintf(stderr, "reading baseline: %s\n", err)
+		return 2
+	}
+
+	old, err := schemadiff.Load(b)
+	if err != nil {
+		fmt.Fprintf(stderr, "reading baseline %s: %s\n", baseline, err)
+		return 2
+	}
+
+	current, err := schemadiff.FromSDL(sdl)
+	if err != nil {
+		fmt.Fprintf(stderr, "reading schema: %s\n", err)
+		return 2
+	}
+
+	changes := schemadiff.Compare(old, current)
+	counts := map[schemadiff.Level]int{}
+
+	for _, c := range changes {
+		fmt.Fprintln(stdout, c)
+		counts[c.Level]++
+	}
+
+	fmt.Fprintf(stdout, "%d breaking, %d dangerous, %d safe changes\n",
+		counts[schemadiff.Breaking], counts[schemadiff.Dangerous], counts[schemadiff.Safe])
+
+	if schemadiff.HasBreaking(changes) {
+		return 1
+	}
+
+	return 0
+}
+
+func schemaLint(sdl string, stdout, stderr io.Writer) int {
+	problems, err := schemalint.Lint(sdl)
+	if err != nil {
+		fmt.Fprintf(stderr, "reading schema: %s\n", err)
+		return 2
+	}
+
+	for _, p := range problems {
+		fmt.Fprintln(stdout, p)
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(stdout, "%d problems\n", len(problems))
+		return 1
+	}
+
+	return 0
+}
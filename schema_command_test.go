@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/schema"
+)
+
+// extraType is added to baselines, so this schema removes it, which is a breaking change.
+const extraType = `
+"An extra type."
+type Extra {
+  "A field."
+  a: Int
+}
+`
+
+// runSchema runs the schema subcommand.
+func runSchema(t *testing.T, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+
+	var out, errs bytes.Buffer
+	code = schemaCommand(args, &out, &errs)
+
+	return out.String(), errs.String(), code
+}
+
+// writeBaseline writes a baseline schema to a file, and returns its path.
+func writeBaseline(t *testing.T, name, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+// introspect returns the result of an introspection query on the schema, as JSON.
+func introspect(t *testing.T, sdl string) string {
+	t.Helper()
+
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	require.NoError(t, err)
+
+	b, err := s.ToJSON()
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestSchemaCommand(t *testing.T) {
+	sdl, err := schema.String()
+	require.NoError(t, err)
+
+	t.Run("Print", func(t *testing.T) {
+		stdout, stderr, code := runSchema(t, "print")
+		require.Equal(t, 0, code)
+		require.Empty(t, stderr)
+		require.Equal(t, sdl, stdout)
+	})
+
+	t.Run("Introspect", func(t *testing.T) {
+		stdout, stderr, code := runSchema(t, "introspect")
+		require.Equal(t, 0, code)
+		require.Empty(t, stderr)
+
+		var res struct {
+			Schema struct {
+				QueryType struct{ Name string }
+			} `json:"__schema"`
+		}
+
+		require.NoError(t, json.Unmarshal([]byte(stdout), &res))
+		require.Equal(t, "Query", res.Schema.QueryType.Name)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		for _, tt := range []struct {
+			name     string
+			baseline string
+			code     int
+			stdout   string
+		}{
+			{
+				name:     "unchanged.graphql",
+				baseline: sdl,
+				stdout:   "0 breaking, 0 dangerous, 0 safe changes\n",
+			},
+			{
+				name:     "unchanged.json",
+				baseline: introspect(t, sdl),
+				stdout:   "0 breaking, 0 dangerous, 0 safe changes\n",
+			},
+			{
+				name:     "breaking.graphql",
+				baseline: sdl + extraType,
+				code:     1,
+				stdout:   "BREAKING  Extra: type was removed\n1 breaking, 0 dangerous, 0 safe changes\n",
+			},
+			{
+				name:     "breaking.json",
+				baseline: introspect(t, sdl+extraType),
+				code:     1,
+				stdout:   "BREAKING  Extra: type was removed\n1 breaking, 0 dangerous, 0 safe changes\n",
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				stdout, stderr, code := runSchema(t, "diff", writeBaseline(t, tt.name, tt.baseline))
+				require.Equal(t, tt.code, code)
+				require.Empty(t, stderr)
+				require.Equal(t, tt.stdout, stdout)
+			})
+		}
+
+		_, stderr, code := runSchema(t, "diff", writeBaseline(t, "invalid.graphql", "nope"))
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "reading baseline ")
+
+		_, stderr, code = runSchema(t, "diff", filepath.Join(t.TempDir(), "missing.graphql"))
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "reading baseline: ")
+	})
+
+	t.Run("Lint", func(t *testing.T) {
+		stdout, stderr, code := runSchema(t, "lint")
+		require.Equal(t, 0, code)
+		require.Empty(t, stderr)
+		require.Empty(t, stdout)
+
+		var out, errs bytes.Buffer
+		code = schemaLint(`type Query { films: [String] }`, &out, &errs)
+		require.Equal(t, 1, code)
+		require.Empty(t, errs.String())
+		require.Contains(t, out.String(), "Query.films")
+		require.Contains(t, out.String(), " problems\n")
+
+		out.Reset()
+		code = schemaLint(`nope`, &out, &errs)
+		require.Equal(t, 2, code)
+		require.Contains(t, errs.String(), "reading schema: ")
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"frob"},
+			{"print", "extra"},
+			{"introspect", "extra"},
+			{"diff"},
+			{"diff", "a", "b"},
+			{"lint", "extra"},
+			{"-nope", "print"},
+		} {
+			stdout, stderr, code := runSchema(t, args...)
+			require.Equal(t, 2, code, "%q", args)
+			require.Empty(t, stdout, "%q", args)
+			require.Contains(t, stderr, "usage: graphql-go-example schema", "%q", args)
+		}
+	})
+}
@@ -0,0 +1,392 @@
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Level classifies how a change affects existing clients.
+type Level int
+
+const (
+	// Safe changes cannot affect existing clients.
+	Safe Level = iota
+	// Dangerous changes keep existing operations valid, but may change how clients behave, such as
+	// a new enum value which a client does not handle.
+	Dangerous
+	// Breaking changes make existing operations invalid, or return values clients cannot expect.
+	Breaking
+)
+
+func (l Level) String() string {
+	switch l {
+	case Breaking:
+		return "BREAKING"
+	case Dangerous:
+		return "DANGEROUS"
+	}
+
+	return "SAFE"
+}
+
+// A Change is a difference between two schemas.
+type Change struct {
+	Level Level
+	// Path is the schema coordinate of the change, such as "Film.title" or "Query.films(title:)".
+	Path    string
+	Message string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%-9s %s: %s", c.Level, c.Path, c.Message)
+}
+
+// Compare returns the changes between the old and new schema, most severe first.
+func Compare(old, new *Schema) []Change {
+	var d differ
+
+	oldTypes, newTypes := typesByName(old), typesByName(new)
+
+	for _, name := range union(keys(oldTypes), keys(newTypes)) {
+		o, n := oldTypes[name], newTypes[name]
+
+		switch {
+		case n == nil:
+			d.add(Breaking, name, "type was removed")
+		case o == nil:
+			d.add(Safe, name, "type was added")
+		case o.Kind != n.Kind:
+			d.add(Breaking, name, fmt.Sprintf("kind changed from %s to %s", o.Kind, n.Kind))
+		default:
+			d.compareType(o, n)
+		}
+	}
+
+	oldDirs, newDirs := directivesByName(old), directivesByName(new)
+
+	for _, name := range union(keys(oldDirs), keys(newDirs)) {
+		o, n := oldDirs[name], newDirs[name]
+		path := "@" + name
+
+		switch {
+		case n == nil:
+			d.add(Breaking, path, "directive was removed")
+		case o == nil:
+			d.add(Safe, path, "directive was added")
+		default:
+			for _, loc := range o.Locations {
+				if !contains(n.Locations, loc) {
+					d.add(Breaking, path, fmt.Sprintf("location %s was removed", loc))
+				}
+			}
+
+			for _, loc := range n.Locations {
+				if !contains(o.Locations, loc) {
+					d.add(Safe, path, fmt.Sprintf("location %s was added", loc))
+				}
+			}
+
+			d.compareArgs(path, o.Args, n.Args)
+		}
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Level != d.changes[j].Level {
+			return d.changes[i].Level > d.changes[j].Level
+		}
+
+		return d.changes[i].Path < d.changes[j].Path
+	})
+
+	return d.changes
+}
+
+// HasBreaking reports whether any of the changes is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Level == Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(l Level, path, msg string) {
+	d.changes = append(d.changes, Change{Level: l, Path: path, Message: msg})
+}
+
+func (d *differ) compareType(o, n *Type) {
+	switch o.Kind {
+	case "OBJECT", "INTERFACE":
+		d.compareFields(o, n)
+		d.compareMembers(o.Name, refNames(o.Interfaces), refNames(n.Interfaces), "interface")
+	case "UNION":
+		d.compareMembers(o.Name, refNames(o.PossibleTypes), refNames(n.PossibleTypes), "member type")
+	case "INPUT_OBJECT":
+		d.compareInputFields(o, n)
+	case "ENUM":
+		d.compareEnumValues(o, n)
+	}
+}
+
+func (d *differ) compareFields(o, n *Type) {
+	oldFields, newFields := map[string]*Field{}, map[string]*Field{}
+	for _, f := range o.Fields {
+		oldFields[f.Name] = f
+	}
+
+	for _, f := range n.Fields {
+		newFields[f.Name] = f
+	}
+
+	for _, name := range union(keys(oldFields), keys(newFields)) {
+		of, nf := oldFields[name], newFields[name]
+		path := o.Name + "." + name
+
+		switch {
+		case nf == nil:
+			d.add(Breaking, path, "field was removed")
+		case of == nil:
+			d.add(Safe, path, "field was added")
+		default:
+			if of.Type.String() != nf.Type.String() {
+				level := Breaking
+				if safeOutputChange(of.Type, nf.Type) {
+					level = Safe
+				}
+
+				d.add(level, path, fmt.Sprintf("type changed from %s to %s", of.Type, nf.Type))
+			}
+
+			switch {
+			case !of.IsDeprecated && nf.IsDeprecated:
+				d.add(Safe, path, "field was deprecated")
+			case of.IsDeprecated && !nf.IsDeprecated:
+				d.add(Safe, path, "field is no longer deprecated")
+			}
+
+			d.compareArgs(path, of.Args, nf.Args)
+		}
+	}
+}
+
+// compareArgs compares the arguments of a field or directive.
+func (d *differ) compareArgs(owner string, o, n []*InputValue) {
+	oldArgs, newArgs := inputsByName(o), inputsByName(n)
+
+	for _, name := range union(keys(oldArgs), keys(newArgs)) {
+		oa, na := oldArgs[name], newArgs[name]
+		path := owner + "(" + name + ":)"
+
+		switch {
+		case na == nil:
+			d.add(Breaking, path, "argument was removed")
+		case oa == nil && required(na):
+			d.add(Breaking, path, "required argument was added")
+		case oa == nil:
+			d.add(Dangerous, path, "optional argument was added")
+		default:
+			d.compareInput(path, oa, na)
+		}
+	}
+}
+
+func (d *differ) compareInputFields(o, n *Type) {
+	oldFields, newFields := inputsByName(o.InputFields), inputsByName(n.InputFields)
+
+	for _, name := range union(keys(oldFields), keys(newFields)) {
+		of, nf := oldFields[name], newFields[name]
+		path := o.Name + "." + name
+
+		switch {
+		case nf == nil:
+			d.add(Breaking, path, "input field was removed")
+		case of == nil && required(nf):
+			d.add(Breaking, path, "required input field was added")
+		case of == nil:
+			d.add(Dangerous, path, "optional input field was added")
+		default:
+			d.compareInput(path, of, nf)
+		}
+	}
+}
+
+// compareInput compares an argument or input field which exists in both schemas.
+func (d *differ) compareInput(path string, o, n *InputValue) {
+	if o.Type.String() != n.Type.String() {
+		level := Breaking
+		if safeInputChange(o.Type, n.Type) {
+			level = Safe
+		}
+
+		d.add(level, path, fmt.Sprintf("type changed from %s to %s", o.Type, n.Type))
+	}
+
+	if value(o.DefaultValue) != value(n.DefaultValue) {
+		d.add(Dangerous, path, fmt.Sprintf("default value changed from %s to %s", value(o.DefaultValue), value(n.DefaultValue)))
+	}
+}
+
+func (d *differ) compareEnumValues(o, n *Type) {
+	oldValues, newValues := map[string]*EnumValue{}, map[string]*EnumValue{}
+	for _, v := range o.EnumValues {
+		oldValues[v.Name] = v
+	}
+
+	for _, v := range n.EnumValues {
+		newValues[v.Name] = v
+	}
+
+	for _, name := range union(keys(oldValues), keys(newValues)) {
+		ov, nv := oldValues[name], newValues[name]
+		path := o.Name + "." + name
+
+		switch {
+		case nv == nil:
+			d.add(Breaking, path, "enum value was removed")
+		case ov == nil:
+			d.add(Dangerous, path, "enum value was added; clients may not handle it")
+		case !ov.IsDeprecated && nv.IsDeprecated:
+			d.add(Safe, path, "enum value was deprecated")
+		}
+	}
+}
+
+// compareMembers compares the interfaces of an object or interface, or the members of a union.
+func (d *differ) compareMembers(owner string, o, n []string, what string) {
+	for _, name := range o {
+		if !contains(n, name) {
+			d.add(Breaking, owner, fmt.Sprintf("%s %s was removed", what, name))
+		}
+	}
+
+	for _, name := range n {
+		if !contains(o, name) {
+			d.add(Dangerous, owner, fmt.Sprintf("%s %s was added", what, name))
+		}
+	}
+}
+
+// safeOutputChange reports whether a field may change from the old type to the new type without
+// breaking clients: it may only become more specific, such as from nullable to non-null.
+func safeOutputChange(o, n *TypeRef) bool {
+	switch o.Kind {
+	case "NON_NULL":
+		return n.Kind == "NON_NULL" && safeOutputChange(o.OfType, n.OfType)
+	case "LIST":
+		return n.Kind == "LIST" && safeOutputChange(o.OfType, n.OfType) ||
+			n.Kind == "NON_NULL" && safeOutputChange(o, n.OfType)
+	}
+
+	return n.Kind == "NON_NULL" && safeOutputChange(o, n.OfType) || n.Name != nil && o.Name != nil && *n.Name == *o.Name
+}
+
+// safeInputChange reports whether an argument or input field may change from the old type to the
+// new type without breaking clients: it may only become more permissive, such as from non-null to
+// nullable.
+func safeInputChange(o, n *TypeRef) bool {
+	switch o.Kind {
+	case "NON_NULL":
+		return n.Kind == "NON_NULL" && safeInputChange(o.OfType, n.OfType) ||
+			n.Kind != "NON_NULL" && safeInputChange(o.OfType, n)
+	case "LIST":
+		return n.Kind == "LIST" && safeInputChange(o.OfType, n.OfType)
+	}
+
+	return n.Kind == o.Kind && n.Name != nil && o.Name != nil && *n.Name == *o.Name
+}
+
+// required reports whether an argument or input field must be provided.
+func required(v *InputValue) bool {
+	return v.Type.Kind == "NON_NULL" && v.DefaultValue == nil
+}
+
+func value(s *string) string {
+	if s == nil {
+		return "none"
+	}
+
+	return *s
+}
+
+func typesByName(s *Schema) map[string]*Type {
+	m := make(map[string]*Type, len(s.Types))
+
+	for _, t := range s.Types {
+		if !strings.HasPrefix(t.Name, "__") {
+			m[t.Name] = t
+		}
+	}
+
+	return m
+}
+
+func directivesByName(s *Schema) map[string]*Directive {
+	m := make(map[string]*Directive, len(s.Directives))
+	for _, d := range s.Directives {
+		m[d.Name] = d
+	}
+
+	return m
+}
+
+func inputsByName(vs []*InputValue) map[string]*InputValue {
+	m := make(map[string]*InputValue, len(vs))
+	for _, v := range vs {
+		m[v.Name] = v
+	}
+
+	return m
+}
+
+func refNames(refs []*TypeRef) []string {
+	names := make([]string, 0, len(refs))
+	for _, r := range refs {
+		names = append(names, r.String())
+	}
+
+	return names
+}
+
+func keys[V any](m map[string]V) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+
+	return ks
+}
+
+// union returns the sorted, distinct names of both lists.
+func union(a, b []string) []string {
+	seen := map[string]bool{}
+
+	var out []string
+
+	for _, s := range append(append([]string(nil), a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package schemadiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/schemadiff"
+)
+
+const oldSDL = `
+schema { query: Query }
+
+type Query {
+	films(title: String, first: Int = 10): [Film!]
+	film(id: ID!): Film
+	people: [Person]
+}
+
+type Film {
+	id: ID!
+	title: String
+	episode: Int!
+	director: String
+	unit: Unit
+}
+
+type Person { name: String }
+
+union Result = Film | Person
+
+enum Unit { METER FOOT }
+
+input Filter { title: String, exact: Boolean }
+
+directive @cached(maxAge: Int) on FIELD_DEFINITION | OBJECT
+`
+
+const newSDL = `
+schema { query: Query }
+
+type Query {
+	films(title: String, first: Int = 20, after: String): [Film!]
+	film(id: ID!, locale: String!): Film
+	people: [Person!]!
+	planets: [Planet]
+}
+
+type Film {
+	id: ID!
+	title: String!
+	episode: Int
+	unit: Unit
+}
+
+type Person { name: String @deprecated(reason: "Use fullName.") fullName: String }
+
+type Planet { name: String }
+
+union Result = Film | Planet
+
+enum Unit { METER FOOT INCH }
+
+input Filter { title: String!, exact: Boolean, limit: Int }
+
+directive @cached(maxAge: Int) on FIELD_DEFINITION
+`
+
+func TestCompare(t *testing.T) {
+	old, err := schemadiff.FromSDL(oldSDL)
+	require.NoError(t, err)
+
+	new, err := schemadiff.FromSDL(newSDL)
+	require.NoError(t, err)
+
+	var got []string
+	for _, c := range schemadiff.Compare(old, new) {
+		got = append(got, c.String())
+	}
+
+	require.Equal(t, []string{
+		"BREAKING  @cached: location OBJECT was removed",
+		"BREAKING  Film.director: field was removed",
+		"BREAKING  Film.episode: type changed from Int! to Int",
+		"BREAKING  Filter.title: type changed from String to String!",
+		"BREAKING  Query.film(locale:): required argument was added",
+		"BREAKING  Result: member type Person was removed",
+		"DANGEROUS Filter.limit: optional input field was added",
+		"DANGEROUS Query.films(after:): optional argument was added",
+		"DANGEROUS Query.films(first:): default value changed from 10 to 20",
+		"DANGEROUS Result: member type Planet was added",
+		"DANGEROUS Unit.INCH: enum value was added; clients may not handle it",
+		"SAFE      Film.title: type changed from String to String!",
+		"SAFE      Person.fullName: field was added",
+		"SAFE      Person.name: field was deprecated",
+		"SAFE      Planet: type was added",
+		"SAFE      Query.people: type changed from [Person] to [Person!]!",
+		"SAFE      Query.planets: field was added",
+	}, got)
+
+	require.True(t, schemadiff.HasBreaking(schemadiff.Compare(old, new)))
+	require.False(t, schemadiff.HasBreaking(schemadiff.Compare(new, new)))
+	require.Empty(t, schemadiff.Compare(old, old))
+}
+
+func TestLoad(t *testing.T) {
+	fromSDL, err := schemadiff.Load([]byte(oldSDL))
+	require.NoError(t, err)
+
+	for _, result := range []string{
+		`{"data": {"__schema": {"types": [{"kind": "OBJECT", "name": "Query"}]}}}`,
+		`{"__schema": {"types": [{"kind": "OBJECT", "name": "Query"}]}}`,
+	} {
+		s, err := schemadiff.Load([]byte(result))
+		require.NoError(t, err)
+		require.Len(t, s.Types, 1)
+	}
+
+	_, err = schemadiff.Load([]byte(`{"data": null}`))
+	require.Error(t, err)
+
+	// The types removed from the SDL are reported against an introspection result.
+	s, err := schemadiff.Load([]byte(`{"__schema": {"types": []}}`))
+	require.NoError(t, err)
+	require.True(t, schemadiff.HasBreaking(schemadiff.Compare(fromSDL, s)))
+}
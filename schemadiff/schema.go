@@ -0,0 +1,134 @@
+// Package schemadiff compares two versions of a GraphQL schema and classifies every change as
+// breaking, dangerous or safe for existing clients.
+package schemadiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// A Schema is a schema as described by the result of an introspection query.
+type Schema struct {
+	QueryType        *namedRef    `json:"queryType"`
+	MutationType     *namedRef    `json:"mutationType"`
+	SubscriptionType *namedRef    `json:"subscriptionType"`
+	Types            []*Type      `json:"types"`
+	Directives       []*Directive `json:"directives"`
+}
+
+type namedRef struct {
+	Name string `json:"name"`
+}
+
+// A Type is a named type of a schema.
+type Type struct {
+	Kind          string        `json:"kind"`
+	Name          string        `json:"name"`
+	Fields        []*Field      `json:"fields"`
+	InputFields   []*InputValue `json:"inputFields"`
+	Interfaces    []*TypeRef    `json:"interfaces"`
+	EnumValues    []*EnumValue  `json:"enumValues"`
+	PossibleTypes []*TypeRef    `json:"possibleTypes"`
+}
+
+// A Field is a field of an object or interface type.
+type Field struct {
+	Name              string        `json:"name"`
+	Args              []*InputValue `json:"args"`
+	Type              *TypeRef      `json:"type"`
+	IsDeprecated      bool          `json:"isDeprecated"`
+	DeprecationReason *string       `json:"deprecationReason"`
+}
+
+// An InputValue is an argument or a field of an input object type.
+type InputValue struct {
+	Name         string   `json:"name"`
+	Type         *TypeRef `json:"type"`
+	DefaultValue *string  `json:"defaultValue"`
+}
+
+// An EnumValue is a value of an enum type.
+type EnumValue struct {
+	Name         string `json:"name"`
+	IsDeprecated bool   `json:"isDeprecated"`
+}
+
+// A Directive is a directive definition.
+type Directive struct {
+	Name      string        `json:"name"`
+	Locations []string      `json:"locations"`
+	Args      []*InputValue `json:"args"`
+}
+
+// A TypeRef refers to a type, possibly wrapped in lists and non-null types.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+func (t *TypeRef) String() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.Kind == "NON_NULL":
+		return t.OfType.String() + "!"
+	case t.Kind == "LIST":
+		return "[" + t.OfType.String() + "]"
+	case t.Name != nil:
+		return *t.Name
+	}
+
+	return ""
+}
+
+// FromSDL reads a schema from its schema definition language.
+func FromSDL(sdl string) (*Schema, error) {
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return FromIntrospection(b)
+}
+
+// FromIntrospection reads a schema from the JSON result of an introspection query, with or without
+// the enclosing "data" member.
+func FromIntrospection(b []byte) (*Schema, error) {
+	var res struct {
+		Data *struct {
+			Schema *Schema `json:"__schema"`
+		} `json:"data"`
+		Schema *Schema `json:"__schema"`
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("decoding introspection result: %w", err)
+	}
+
+	switch {
+	case res.Schema != nil:
+		return res.Schema, nil
+	case res.Data != nil && res.Data.Schema != nil:
+		return res.Data.Schema, nil
+	}
+
+	return nil, fmt.Errorf("introspection result has no __schema")
+}
+
+// Load reads a schema from either an introspection result in JSON or SDL.
+func Load(b []byte) (*Schema, error) {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		return FromIntrospection(trimmed)
+	}
+
+	return FromSDL(string(b))
+}
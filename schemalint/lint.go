@@ -0,0 +1,246 @@
+// Package schemalint checks a GraphQL schema against the conventions of this API.
+//
+// The rules are:
+//
+//   - documented: every type, field, argument, input field, enum value and directive has a
+//     description.
+//   - naming: types are PascalCase, fields, arguments and directives are camelCase, and enum values
+//     are SCREAMING_SNAKE_CASE. Names defined by specifications such as Apollo Federation may
+//     start with an underscore, such as _Entity and _service.
+//   - units: numeric fields which measure a dimension, such as a height or a period, take a unit
+//     argument whose type is an enum named *Unit, with a default value.
+//   - lists: lists of objects on resource types have nullable items, so an entry which fails to
+//     load is null on its own rather than failing the whole list. Lists of scalars and enums are
+//     non-null lists of non-null items. Fields of the root types are exempt, since their entries are
+//     fetched together.
+//   - auth: fields restricted with @auth are nullable, since they resolve to null for callers who
+//     may not access them.
+package schemalint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/introspection"
+
+	"github.com/tonyghita/graphql-go-example/schema"
+)
+
+// A Problem is a violation of a rule.
+type Problem struct {
+	Rule string
+	// Coordinate identifies the offending part of the schema, such as "Film.title".
+	Coordinate string
+	Message    string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s (%s)", p.Coordinate, p.Message, p.Rule)
+}
+
+var (
+	pascalCase         = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	camelCase          = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	screamingSnakeCase = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+	// dimensions are the words which mark a field as measuring a dimension.
+	dimensions = regexp.MustCompile(`(?i)(height|length|width|depth|diameter|radius|distance|mass|weight|speed|period|lifespan|cargo)`)
+)
+
+// The built-in scalars and directives are defined by the GraphQL specification, not by this API.
+var builtins = map[string]bool{
+	"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true,
+	"include": true, "skip": true, "deprecated": true, "specifiedBy": true,
+}
+
+// Lint returns the problems found in the schema, sorted by coordinate.
+func Lint(sdl string) ([]Problem, error) {
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	if err != nil {
+		return nil, err
+	}
+
+	directives, err := schema.ParseDirectives(sdl)
+	if err != nil {
+		return nil, err
+	}
+
+	inspected := s.Inspect()
+
+	roots := map[string]bool{}
+	for _, t := range []*introspection.Type{inspected.QueryType(), inspected.MutationType(), inspected.SubscriptionType()} {
+		if t != nil {
+			roots[*t.Name()] = true
+		}
+	}
+
+	var l linter
+
+	for _, t := range inspected.Types() {
+		name := *t.Name()
+		if strings.HasPrefix(name, "__") || builtins[name] {
+			continue
+		}
+
+		l.documented(name, t.Description())
+		l.name(name, name, pascalCase, "PascalCase")
+
+		all := &struct{ IncludeDeprecated bool }{true}
+
+		if fields := t.Fields(all); fields != nil {
+			for _, f := range *fields {
+				coordinate := name + "." + f.Name()
+
+				l.documented(coordinate, f.Description())
+				l.name(coordinate, f.Name(), camelCase, "camelCase")
+				l.units(coordinate, f)
+
+				if _, ok := directives.Find(coordinate, "auth"); ok && f.Type().Kind() == "NON_NULL" {
+					l.add("auth", coordinate, "a field restricted with @auth must be nullable")
+				}
+
+				if !roots[name] {
+					l.list(coordinate, f.Type())
+				}
+
+				l.arguments(coordinate, f.Args())
+			}
+		}
+
+		if fields := t.InputFields(); fields != nil {
+			for _, f := range *fields {
+				coordinate := name + "." + f.Name()
+
+				l.documented(coordinate, f.Description())
+				l.name(coordinate, f.Name(), camelCase, "camelCase")
+			}
+		}
+
+		if values := t.EnumValues(all); values != nil {
+			for _, v := range *values {
+				coordinate := name + "." + v.Name()
+
+				l.documented(coordinate, v.Description())
+				l.name(coordinate, v.Name(), screamingSnakeCase, "SCREAMING_SNAKE_CASE")
+			}
+		}
+	}
+
+	for _, d := range inspected.Directives() {
+		if builtins[d.Name()] {
+			continue
+		}
+
+		coordinate := "@" + d.Name()
+
+		l.documented(coordinate, d.Description())
+		l.name(coordinate, d.Name(), camelCase, "camelCase")
+		l.arguments(coordinate, d.Args())
+	}
+
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		return l.problems[i].Coordinate < l.problems[j].Coordinate
+	})
+
+	return l.problems, nil
+}
+
+type linter struct {
+	problems []Problem
+}
+
+func (l *linter) add(rule, coordinate, format string, args ...interface{}) {
+	l.problems = append(l.problems, Problem{Rule: rule, Coordinate: coordinate, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) documented(coordinate string, desc *string) {
+	if desc == nil || strings.TrimSpace(*desc) == "" {
+		l.add("documented", coordinate, "missing description")
+	}
+}
+
+func (l *linter) name(coordinate, name string, convention *regexp.Regexp, conventionName string) {
+	// The leading underscore of names reserved by specifications, such as _Entity, is ignored.
+	if !convention.MatchString(strings.TrimPrefix(name, "_")) {
+		l.add("naming", coordinate, "%q is not %s", name, conventionName)
+	}
+}
+
+func (l *linter) arguments(owner string, args []*introspection.InputValue) {
+	for _, a := range args {
+		coordinate := owner + "(" + a.Name() + ":)"
+
+		l.documented(coordinate, a.Description())
+		l.name(coordinate, a.Name(), camelCase, "camelCase")
+	}
+}
+
+// units checks that fields measuring a dimension take a unit argument.
+func (l *linter) units(coordinate string, f *introspection.Field) {
+	switch *named(f.Type()).Name() {
+	case "Float", "Int":
+	default:
+		return
+	}
+
+	if !dimensions.MatchString(f.Name()) {
+		return
+	}
+
+	for _, a := range f.Args() {
+		if a.Name() != "unit" {
+			continue
+		}
+
+		t := named(a.Type())
+		if t.Kind() != "ENUM" || !strings.HasSuffix(*t.Name(), "Unit") {
+			l.add("units", coordinate, "the unit argument must be an enum named *Unit, not %s", *t.Name())
+		}
+
+		if a.DefaultValue() == nil {
+			l.add("units", coordinate, "the unit argument must have a default value")
+		}
+
+		return
+	}
+
+	l.add("units", coordinate, "a dimensional field must take a unit argument")
+}
+
+// list checks the nullability of list fields.
+func (l *linter) list(coordinate string, t *introspection.Type) {
+	outer := t
+	if t.Kind() == "NON_NULL" {
+		t = t.OfType()
+	}
+
+	if t.Kind() != "LIST" {
+		return
+	}
+
+	item := t.OfType()
+	element := named(item)
+
+	switch element.Kind() {
+	case "OBJECT", "INTERFACE", "UNION":
+		if item.Kind() == "NON_NULL" {
+			l.add("lists", coordinate, "a list of objects must have nullable items, so one failed entry does not fail the list")
+		}
+	default:
+		if outer.Kind() != "NON_NULL" || item.Kind() != "NON_NULL" {
+			l.add("lists", coordinate, "a list of scalars must be a non-null list of non-null items, such as [%s!]!", *element.Name())
+		}
+	}
+}
+
+// named unwraps list and non-null types.
+func named(t *introspection.Type) *introspection.Type {
+	for t.Name() == nil {
+		t = t.OfType()
+	}
+
+	return t
+}
@@ -0,0 +1,64 @@
+package schemalint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/schemalint"
+)
+
+func TestLint(t *testing.T) {
+	problems, err := schemalint.Lint(`
+		"Restricts a field."
+		directive @auth("The role." requires: String!) on FIELD_DEFINITION
+
+		schema { query: Query }
+
+		"The root."
+		type Query {
+			"Search."
+			things(name: String): [Thing!]
+		}
+
+		"A thing."
+		type Thing {
+			"The ID."
+			id: ID!
+			"The price."
+			price: Int! @auth(requires: "INTERNAL")
+			"Height."
+			height: Float
+			"Width."
+			width(unit: String = "cm"): Float
+			related_things: [Thing!]!
+			"Tags."
+			tags: [String]
+		}
+
+		enum LengthUnit {
+			"A meter."
+			meter
+		}
+	`)
+	require.NoError(t, err)
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+
+	require.Equal(t, []string{
+		`LengthUnit: missing description (documented)`,
+		`LengthUnit.meter: "meter" is not SCREAMING_SNAKE_CASE (naming)`,
+		`Query.things(name:): missing description (documented)`,
+		`Thing.height: a dimensional field must take a unit argument (units)`,
+		`Thing.price: a field restricted with @auth must be nullable (auth)`,
+		`Thing.related_things: missing description (documented)`,
+		`Thing.related_things: "related_things" is not camelCase (naming)`,
+		`Thing.related_things: a list of objects must have nullable items, so one failed entry does not fail the list (lists)`,
+		`Thing.tags: a list of scalars must be a non-null list of non-null items, such as [String!]! (lists)`,
+		`Thing.width: the unit argument must be an enum named *Unit, not String (units)`,
+		`Thing.width(unit:): missing description (documented)`,
+	}, got)
+}
@@ -3,20 +3,47 @@
 package main
 
 import (
+	"expvar"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace"
 
+	"github.com/tonyghita/graphql-go-example/auth"
+	"github.com/tonyghita/graphql-go-example/cache"
 	"github.com/tonyghita/graphql-go-example/handler"
+	"github.com/tonyghita/graphql-go-example/limit"
 	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/ratelimit"
 	"github.com/tonyghita/graphql-go-example/resolver"
 	"github.com/tonyghita/graphql-go-example/schema"
 	"github.com/tonyghita/graphql-go-example/swapi"
+	"github.com/tonyghita/graphql-go-example/tracing"
+	"github.com/tonyghita/graphql-go-example/usage"
 )
 
+// loaderSettings configure the loaders created for each request, by the server and the query
+// subcommand alike. Loaders dispatch a batch once no new key has been requested for Wait.
+var loaderSettings = loader.Settings{
+	Default: loader.Options{Wait: 16 * time.Millisecond, MaxBatch: 0}, // Unbounded batches.
+}
+
 func main() {
+	// The schema subcommand inspects the schema instead of serving it, and the query subcommand
+	// executes a single query without serving anything.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "schema":
+			os.Exit(schemaCommand(os.Args[2:], os.Stdout, os.Stderr))
+		case "query":
+			os.Exit(queryCommand(os.Args[2:], os.DirFS("/"), os.Stdin, os.Stdout, os.Stderr))
+		}
+	}
+
 	// Tweak configuration values here.
 	var (
 		addr              = ":8000"
@@ -24,34 +51,200 @@ func main() {
 		writeTimeout      = 10 * time.Second
 		idleTimeout       = 90 * time.Second
 		maxHeaderBytes    = http.DefaultMaxHeaderBytes
+
+		// Serve the administrative endpoints, /admin/usage and /debug/vars, which are not
+		// authenticated, on a separate listener which only accepts local connections. An empty
+		// adminAddr disables them.
+		adminAddr = "127.0.0.1:8001"
+
+		// Hide the details of internal errors from clients; they are logged instead.
+		maskErrors = true
+
+		// Bound the number of concurrent calls to SWAPI, so one heavy query cannot open hundreds of
+		// connections or starve other requests.
+		upstreamLimits = limit.Config{Global: 64, PerHost: 32, PerRequest: 8}
+		// Bound the number of operations of a batched request which execute at once.
+		maxParallelOperations = 4
+
+		// Keep up to this many cacheable responses to GET requests in memory. Zero disables the
+		// response cache; Cache-Control and ETag headers are sent either way.
+		responseCacheSize = 1024
+
+		// Serve the GraphiQL IDE under this path.
+		graphiqlPath = "/"
+
+		// Persist which schema coordinates each client uses to usageFile, every usageFlushInterval,
+		// keeping the daily counts of the last usageRetentionDays. An empty usageFile keeps the
+		// counts in memory only. Clients beyond the first usageMaxClients are counted as unknown.
+		usageFile          = "usage.json"
+		usageFlushInterval = 1 * time.Minute
+		usageRetentionDays = 90
+		usageMaxClients    = 100
+
+		// Authenticate requests by the API keys listed in apiKeysFile, and by JWTs signed with the
+		// keys of the JSON Web Key Sets in jwksFiles. Either is disabled when empty. Anonymous
+		// requests are rejected when requireAuthentication is set.
+		apiKeysFile           = ""
+		jwksFiles             = []string{}
+		jwtIssuer             = ""
+		jwtAudience           = ""
+		requireAuthentication = false
+		// Remove fields restricted with @auth from the introspection results of callers who may not
+		// access them.
+		hideForbiddenFields = false
+
+		// Limit each client, identified by its principal or else its IP address, to a number of
+		// requests and a total estimated query cost, which refill evenly over a minute. Principals
+		// granted a role in rateLimitTiers get that tier's limits. A zero Limit is unlimited.
+		anonymousRateLimit = ratelimit.Tier{
+			Requests: ratelimit.Limit{Capacity: 60, Per: time.Minute},
+			Cost:     ratelimit.Limit{Capacity: 2000, Per: time.Minute},
+		}
+		authenticatedRateLimit = ratelimit.Tier{
+			Requests: ratelimit.Limit{Capacity: 300, Per: time.Minute},
+			Cost:     ratelimit.Limit{Capacity: 10000, Per: time.Minute},
+		}
+		rateLimitTiers = map[string]ratelimit.Tier{
+			"internal": {},
+		}
+
+		// Let browser applications served from corsAllowedOrigins send requests to /graphql. An
+		// origin may use a wildcard subdomain, such as "https://*.example.com". No origin is
+		// allowed when the list is empty.
+		corsAllowedOrigins   = []string{}
+		corsAllowedMethods   = []string{http.MethodGet, http.MethodPost}
+		corsAllowedHeaders   = []string{"Content-Type", "Authorization", auth.APIKeyHeader, "X-Request-ID", "X-Debug", "apollographql-client-name", "apollographql-client-version"}
+		corsExposedHeaders   = []string{"X-Request-ID", "ETag", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"}
+		corsAllowCredentials = false
+		corsMaxAge           = 10 * time.Minute
+
+		// Add Apollo tracing, and the loader batches and SWAPI calls of each operation, to the
+		// extensions of responses to requests sent with an "X-Debug: true" header by principals
+		// granted debugRole. An empty debugRole lets anyone ask.
+		debugExtensions = true
+		debugRole       = "internal"
 	)
 
 	log.SetFlags(log.Lshortfile | log.LstdFlags)
 
-	c := swapi.NewClient(http.DefaultClient) // TODO: don't use the default client.
+	limiter := limit.New(upstreamLimits)
+	expvar.Publish("upstream_active", expvar.Func(func() interface{} { return limiter.Active() }))
+	expvar.Publish("upstream_queue_depth", expvar.Func(func() interface{} { return limiter.QueueDepth() }))
 
-	root, err := resolver.NewRoot(c)
-	if err != nil {
-		log.Fatalf("creating root resolver: %s", err)
-	}
+	c := swapi.NewClient(&http.Client{Transport: upstreamTransport(limiter, http.DefaultTransport)})
 
 	s, err := schema.String()
 	if err != nil {
 		log.Fatalf("reading embedded schema contents: %s", err)
 	}
 
+	// Parse and validate schema. Exit if unable to do so.
+	exec, err := newSchema(s, c)
+	if err != nil {
+		log.Fatalf("creating schema: %s", err)
+	}
+
+	var responses *cache.Responses
+	if responseCacheSize > 0 {
+		responses = cache.NewResponses(responseCacheSize)
+	}
+
+	index, err := usage.NewIndex(s)
+	if err != nil {
+		log.Fatalf("indexing schema coordinates: %s", err)
+	}
+
+	tracker, err := usage.Open(index, usageFile, usageRetentionDays, usageMaxClients)
+	if err != nil {
+		log.Fatalf("reading schema usage: %s", err)
+	}
+
+	go func() {
+		for now := range time.Tick(usageFlushInterval) {
+			if err := tracker.Flush(now); err != nil {
+				log.Printf("saving schema usage: %s", err)
+			}
+		}
+	}()
+
+	rules, err := auth.NewRules(s)
+	if err != nil {
+		log.Fatalf("reading authorization rules: %s", err)
+	}
+
+	var authenticators auth.Chain
+
+	if len(jwksFiles) > 0 {
+		keys, err := auth.LoadJWKS(jwksFiles...)
+		if err != nil {
+			log.Fatalf("reading JSON Web Key Sets: %s", err)
+		}
+
+		authenticators = append(authenticators, auth.JWT{Keys: keys, Issuer: jwtIssuer, Audience: jwtAudience})
+	}
+
+	if apiKeysFile != "" {
+		keys, err := auth.LoadAPIKeys(apiKeysFile)
+		if err != nil {
+			log.Fatalf("reading API keys: %s", err)
+		}
+
+		authenticators = append(authenticators, keys)
+	}
+
+	costs, err := ratelimit.NewCosts(s)
+	if err != nil {
+		log.Fatalf("reading field types for query costs: %s", err)
+	}
+
+	rateLimits := &ratelimit.Limiter{
+		Store:         ratelimit.NewMemory(),
+		Costs:         costs,
+		Anonymous:     anonymousRateLimit,
+		Authenticated: authenticatedRateLimit,
+		Tiers:         rateLimitTiers,
+	}
+
 	// Create the request handler; inject dependencies.
 	h := handler.GraphQL{
-		// Parse and validate schema. Panic if unable to do so.
-		Schema:  graphql.MustParseSchema(s, root),
-		Loaders: loader.Initialize(c),
+		Schema:  exec,
+		Loaders: loader.Initialize(c, loaderSettings),
+		Logger:  log.Default(),
+
+		MaskErrors:            maskErrors,
+		MaxParallelOperations: maxParallelOperations,
+		Responses:             responses,
+		Usage:                 tracker,
+		Authenticator:         authenticators,
+		RequireAuthentication: requireAuthentication,
+		Authorization:         rules,
+		HideForbiddenFields:   hideForbiddenFields,
+		RateLimits:            rateLimits,
+		Debug:                 debugExtensions,
+		DebugRole:             debugRole,
+	}
+
+	// Let browsers on other origins call the API.
+	api := handler.CORS{
+		Handler:          h,
+		AllowedOrigins:   corsAllowedOrigins,
+		AllowedMethods:   corsAllowedMethods,
+		AllowedHeaders:   corsAllowedHeaders,
+		ExposedHeaders:   corsExposedHeaders,
+		AllowCredentials: corsAllowCredentials,
+		MaxAge:           corsMaxAge,
 	}
 
 	// Register handlers to routes.
 	mux := http.NewServeMux()
-	mux.Handle("/", handler.GraphiQL{})
-	mux.Handle("/graphql/", h)
-	mux.Handle("/graphql", h) // Register without a trailing slash to avoid redirect.
+	mux.Handle(graphiqlPath, handler.GraphiQL{Endpoint: "/graphql", BasePath: graphiqlPath})
+	mux.Handle("/graphql/", api)
+	mux.Handle("/graphql", api) // Register without a trailing slash to avoid redirect.
+
+	// The administrative endpoints are only served by the administrative listener.
+	admin := http.NewServeMux()
+	admin.Handle("/admin/usage", handler.Usage{Tracker: tracker})
+	admin.Handle("/debug/vars", expvar.Handler())
 
 	// Configure the HTTP server.
 	srv := &http.Server{
@@ -63,6 +256,25 @@ func main() {
 		MaxHeaderBytes:    maxHeaderBytes,
 	}
 
+	if adminAddr != "" {
+		adminSrv := &http.Server{
+			Addr:              adminAddr,
+			Handler:           admin,
+			ReadHeaderTimeout: readHeaderTimeout,
+			WriteTimeout:      writeTimeout,
+			IdleTimeout:       idleTimeout,
+			MaxHeaderBytes:    maxHeaderBytes,
+		}
+
+		log.Printf("Listening for administrative requests on %s", adminSrv.Addr)
+
+		go func() {
+			if err := adminSrv.ListenAndServe(); err != nil {
+				log.Println("admin server.ListenAndServe:", err)
+			}
+		}()
+	}
+
 	// Begin listeing for requests.
 	log.Printf("Listening for requests on %s", srv.Addr)
 
@@ -70,6 +282,43 @@ func main() {
 		log.Println("server.ListenAndServe:", err)
 	}
 
+	if err = tracker.Flush(time.Now()); err != nil {
+		log.Printf("saving schema usage: %s", err)
+	}
+
 	// TODO: intercept shutdown signals for cleanup of connections.
 	log.Println("Shut down.")
 }
+
+// newSchema parses the schema, with resolvers which fetch resources with the client. The server
+// and the query subcommand execute the same schema.
+func newSchema(sdl string, c *swapi.Client) (*graphql.Schema, error) {
+	root, err := resolver.NewRoot(c)
+	if err != nil {
+		return nil, fmt.Errorf("creating root resolver: %w", err)
+	}
+
+	hints, err := cache.NewHints(sdl)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache hints: %w", err)
+	}
+
+	types, err := tracing.NewTypes(sdl)
+	if err != nil {
+		return nil, fmt.Errorf("reading field types for tracing: %w", err)
+	}
+
+	// Record the cache hints of resolved fields, so each response gets a cache policy, and the
+	// resolvers of traced operations.
+	tracer := cache.Tracer{Tracer: tracing.Tracer{Tracer: trace.OpenTracingTracer{}, Types: types}, Hints: hints}
+
+	return graphql.ParseSchema(sdl, root, graphql.UseStringDescriptions(), graphql.Tracer(tracer))
+}
+
+// upstreamTransport sends the SWAPI calls of the server and the query subcommand through base.
+// Each caller acquires a slot of its own request's limits, then records the call for tracing once
+// it has the slot, so the traced latency excludes queueing. Identical concurrent calls are then
+// coalesced: every caller waiting for a shared call holds its slot until the call has finished.
+func upstreamTransport(l *limit.Limiter, base http.RoundTripper) http.RoundTripper {
+	return l.Transport(tracing.Transport(swapi.Coalesce(base)))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/limit"
+	"github.com/tonyghita/graphql-go-example/tracing"
+)
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestUpstreamTransport(t *testing.T) {
+	const (
+		calls   = 10
+		latency = 25 * time.Millisecond
+	)
+
+	var (
+		mu           sync.Mutex
+		active, peak int
+	)
+
+	base := roundTripper(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		active++
+		if active > peak {
+			peak = active
+		}
+		mu.Unlock()
+
+		time.Sleep(latency)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+
+	c := &http.Client{Transport: upstreamTransport(limit.New(limit.Config{PerRequest: 2}), base)}
+
+	ctx, tr := tracing.Attach(limit.Attach(context.Background()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		url := fmt.Sprintf("https://swapi.dev/api/films/%d/", i)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			resp, err := c.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+		}()
+	}
+
+	wg.Wait()
+	tr.Finish()
+
+	require.Equal(t, 2, peak, "the calls of a request exceeded its limit")
+
+	traced := tr.Extensions()["debug"].(map[string]interface{})["calls"].([]*tracing.Call)
+	require.Len(t, traced, calls)
+
+	for _, call := range traced {
+		// The last calls queued for four times the latency, none of which is traced.
+		require.Less(t, call.Duration, 3*latency, "%s", call.URL)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -23,6 +24,11 @@ func NewClient(c *http.Client) *Client {
 	return &Client{base: "https://swapi.dev/api", http: c}
 }
 
+// URL returns the URL of the resource of a kind, such as "people", with the ID.
+func (c *Client) URL(kind, id string) string {
+	return c.base + "/" + kind + "/" + id + "/"
+}
+
 func (c *Client) NewRequest(ctx context.Context, url string) (*http.Request, error) {
 	if len(url) == 0 {
 		return nil, errors.New("invalid empty-string url")
@@ -40,22 +46,81 @@ func (c *Client) NewRequest(ctx context.Context, url string) (*http.Request, err
 	return r.WithContext(ctx), nil
 }
 
-// Do the request.
+// Do the request and decode the JSON response body into v.
 func (c *Client) Do(r *http.Request, v interface{}) (*http.Response, error) {
+	resp, body, err := c.fetch(r)
+	if err != nil {
+		return resp, err
+	}
+
+	if v != nil {
+		if err = json.Unmarshal(body, v); err != nil {
+			return nil, fmt.Errorf("unable to parse JSON [%s %s]: %v", r.Method, r.URL.RequestURI(), err)
+		}
+	}
+
+	return resp, nil
+}
+
+// fetch sends the request and reads the response body.
+func (c *Client) fetch(r *http.Request) (*http.Response, []byte, error) {
 	resp, err := c.http.Do(r)
 	if err != nil {
-		return nil, err
+		return nil, nil, &UnavailableError{err: err}
 	}
 
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	if v != nil {
-		if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
-			return nil, fmt.Errorf("unable to parse JSON [%s %s]: %v", r.Method, r.URL.RequestURI(), err)
-		}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, nil, &StatusError{Method: r.Method, URL: r.URL.String(), StatusCode: resp.StatusCode}
 	}
 
-	return resp, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, &UnavailableError{err: err}
+	}
+
+	return resp, body, nil
+}
+
+// A StatusError is returned when the SWAPI REST API responds with an unsuccessful status code.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// NotFound reports whether the requested resource does not exist.
+func (e *StatusError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// Unavailable reports whether the API failed to serve the request, so it may succeed later.
+func (e *StatusError) Unavailable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// An UnavailableError is returned when the SWAPI REST API could not be reached.
+type UnavailableError struct {
+	err error
+}
+
+func (e *UnavailableError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying transport error.
+func (e *UnavailableError) Unwrap() error {
+	return e.err
+}
+
+// Unavailable always returns true: the request never reached the API.
+func (e *UnavailableError) Unavailable() bool {
+	return true
 }
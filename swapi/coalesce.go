@@ -0,0 +1,141 @@
+package swapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Coalesce returns an http.RoundTripper which shares concurrent identical GET requests: while a
+// request for a URL is in progress, further requests for the URL wait for its response instead of
+// sending their own. Each caller reads its own copy of the response.
+//
+// The shared request is sent through the base RoundTripper with a context of its own, which
+// carries none of the values of the callers' contexts. RoundTrippers which trace or limit the
+// calls of each incoming request must wrap the coalescing RoundTripper to see every caller;
+// those it wraps see the shared request as a call of its own.
+func Coalesce(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &coalescing{base: base}
+}
+
+type coalescing struct {
+	base    http.RoundTripper
+	flights flights
+}
+
+func (t *coalescing) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet {
+		return t.base.RoundTrip(r)
+	}
+
+	resp, body, err := t.flights.do(r.Context(), r.URL.String(), func(ctx context.Context) (*http.Response, []byte, error) {
+		resp, err := t.base.RoundTrip(r.Clone(ctx))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return resp, body, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := *resp
+	c.Header = resp.Header.Clone()
+	c.Body = io.NopCloser(bytes.NewReader(body))
+	c.ContentLength = int64(len(body))
+	c.Request = r
+
+	return &c, nil
+}
+
+// A flight is an upstream fetch shared by every caller requesting the same URL while it is in
+// progress.
+type flight struct {
+	done    chan struct{}
+	resp    *http.Response
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// flights coalesces concurrent identical fetches, in the style of singleflight.
+//
+// The shared fetch runs with a context of its own rather than the first caller's, so one caller
+// giving up does not fail the others. The fetch is only cancelled once every caller waiting for it
+// has given up.
+type flights struct {
+	mu       sync.Mutex
+	inFlight map[string]*flight
+}
+
+// do returns the result of fetch for the key, sharing a fetch already in progress for the same key.
+func (g *flights) do(ctx context.Context, key string, fetch func(context.Context) (*http.Response, []byte, error)) (*http.Response, []byte, error) {
+	g.mu.Lock()
+
+	if g.inFlight == nil {
+		g.inFlight = make(map[string]*flight)
+	}
+
+	f, ok := g.inFlight[key]
+	if !ok {
+		fctx, cancel := context.WithCancel(context.Background())
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.inFlight[key] = f
+
+		go g.run(fctx, key, f, fetch)
+	}
+
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.resp, f.body, f.err
+	case <-ctx.Done():
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if f.waiters--; f.waiters == 0 {
+		// Nobody is interested in the result any more. Later callers start a new fetch.
+		f.cancel()
+
+		if g.inFlight[key] == f {
+			delete(g.inFlight, key)
+		}
+	}
+
+	return nil, nil, ctx.Err()
+}
+
+func (g *flights) run(ctx context.Context, key string, f *flight, fetch func(context.Context) (*http.Response, []byte, error)) {
+	f.resp, f.body, f.err = fetch(ctx)
+	f.cancel()
+
+	g.mu.Lock()
+	if g.inFlight[key] == f {
+		delete(g.inFlight, key)
+	}
+	g.mu.Unlock()
+
+	close(f.done)
+}
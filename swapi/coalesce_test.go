@@ -0,0 +1,182 @@
+package swapi_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+	"github.com/tonyghita/graphql-go-example/tracing"
+)
+
+// gate is an http.RoundTripper which blocks every request until it is opened.
+type gate struct {
+	calls     int64
+	status    int
+	open      chan struct{}
+	cancelled chan struct{}
+}
+
+func newGate(status int) *gate {
+	return &gate{status: status, open: make(chan struct{}), cancelled: make(chan struct{}, 1)}
+}
+
+func (g *gate) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&g.calls, 1)
+
+	select {
+	case <-g.open:
+	case <-r.Context().Done():
+		g.cancelled <- struct{}{}
+		return nil, r.Context().Err()
+	}
+
+	body := `{"title": "A New Hope", "url": "https://swapi.dev/api/films/1/"}`
+	return &http.Response{StatusCode: g.status, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+// waitForCalls waits until the gate has received n requests.
+func (g *gate) waitForCalls(t *testing.T, n int64) {
+	t.Helper()
+	require.Eventually(t, func() bool { return atomic.LoadInt64(&g.calls) == n }, time.Second, time.Millisecond)
+}
+
+const filmURL = "https://swapi.dev/api/films/1/"
+
+func TestCoalescing(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Shared", func(t *testing.T) {
+		g := newGate(http.StatusOK)
+		c := swapi.NewClient(&http.Client{Transport: swapi.Coalesce(g)})
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				f, err := c.Film(ctx, filmURL)
+				require.NoError(t, err)
+				require.Equal(t, "A New Hope", f.Title)
+			}()
+		}
+
+		g.waitForCalls(t, 1)
+		time.Sleep(10 * time.Millisecond) // Let the remaining callers join the flight.
+		close(g.open)
+		wg.Wait()
+
+		require.EqualValues(t, 1, g.calls)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		g := newGate(http.StatusNotFound)
+		c := swapi.NewClient(&http.Client{Transport: swapi.Coalesce(g)})
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, err := c.Film(ctx, filmURL)
+				errs <- err
+			}()
+		}
+
+		g.waitForCalls(t, 1)
+		time.Sleep(10 * time.Millisecond)
+		close(g.open)
+
+		for i := 0; i < 2; i++ {
+			var se *swapi.StatusError
+			require.ErrorAs(t, <-errs, &se)
+			require.True(t, se.NotFound())
+		}
+	})
+
+	t.Run("Traced by every caller", func(t *testing.T) {
+		// The shared request carries no caller's trace, so the inner transport records nothing.
+		g := newGate(http.StatusOK)
+		c := swapi.NewClient(&http.Client{Transport: tracing.Transport(swapi.Coalesce(tracing.Transport(g)))})
+
+		traces := make(chan *tracing.Trace, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				tctx, tr := tracing.Attach(ctx)
+				_, err := c.Film(tctx, filmURL)
+				require.NoError(t, err)
+				traces <- tr
+			}()
+		}
+
+		g.waitForCalls(t, 1)
+		time.Sleep(10 * time.Millisecond)
+		close(g.open)
+
+		for i := 0; i < 2; i++ {
+			debug, _ := (<-traces).Extensions()["debug"].(map[string]interface{})
+			require.Len(t, debug["calls"], 1)
+		}
+
+		require.EqualValues(t, 1, g.calls)
+	})
+
+	t.Run("One caller cancels", func(t *testing.T) {
+		g := newGate(http.StatusOK)
+		c := swapi.NewClient(&http.Client{Transport: swapi.Coalesce(g)})
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancelled := make(chan error)
+		go func() {
+			_, err := c.Film(cctx, filmURL)
+			cancelled <- err
+		}()
+
+		g.waitForCalls(t, 1)
+
+		result := make(chan error)
+		go func() {
+			_, err := c.Film(ctx, filmURL)
+			result <- err
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		require.ErrorIs(t, <-cancelled, context.Canceled)
+
+		close(g.open)
+		require.NoError(t, <-result)
+		require.EqualValues(t, 1, g.calls)
+	})
+
+	t.Run("Every caller cancels", func(t *testing.T) {
+		g := newGate(http.StatusOK)
+		c := swapi.NewClient(&http.Client{Transport: swapi.Coalesce(g)})
+
+		cctx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() {
+			_, err := c.Film(cctx, filmURL)
+			done <- err
+		}()
+
+		g.waitForCalls(t, 1)
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+
+		// The abandoned upstream request is cancelled too.
+		select {
+		case <-g.cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("upstream request was not cancelled")
+		}
+	})
+}
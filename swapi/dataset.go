@@ -0,0 +1,191 @@
+package swapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// resourceKinds are the kinds of SWAPI resources, as they appear in resource URLs.
+var resourceKinds = []string{"films", "people", "planets", "species", "starships", "vehicles"}
+
+// searchFields are the fields each kind of resource is searched by.
+var searchFields = map[string][]string{
+	"films":     {"title"},
+	"people":    {"name"},
+	"planets":   {"name"},
+	"species":   {"name"},
+	"starships": {"name", "model"},
+	"vehicles":  {"name", "model"},
+}
+
+// A Dataset serves the SWAPI REST API from files, so this API can be used offline.
+//
+// A dataset is a directory with a file per kind of resource, named after the kind, such as
+// films.json. Each file holds a JSON array of the resources as SWAPI serves them. Kinds without a
+// file have no resources.
+//
+// A Dataset is an http.RoundTripper: a Client using it as its transport fetches resources by their
+// URLs, whatever their host, and searches them like SWAPI does. Search results are never paginated.
+type Dataset struct {
+	kinds map[string]*kind
+}
+
+type kind struct {
+	resources []resource
+	byID      map[string]int
+}
+
+type resource struct {
+	raw json.RawMessage
+	// search holds the lower-cased values of the fields the resource is searched by.
+	search []string
+}
+
+// LoadDataset reads a dataset from the file system.
+func LoadDataset(fsys fs.FS) (*Dataset, error) {
+	d := &Dataset{kinds: make(map[string]*kind, len(resourceKinds))}
+	found := false
+
+	for _, name := range resourceKinds {
+		k := &kind{byID: map[string]int{}}
+		d.kinds[name] = k
+
+		b, err := fs.ReadFile(fsys, name+".json")
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		found = true
+
+		var raws []json.RawMessage
+		if err := json.Unmarshal(b, &raws); err != nil {
+			return nil, fmt.Errorf("reading %s.json: %w", name, err)
+		}
+
+		for i, raw := range raws {
+			var fields map[string]interface{}
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				return nil, fmt.Errorf("reading %s.json: resource %d: %w", name, i, err)
+			}
+
+			u, _ := fields["url"].(string)
+
+			id := resourceID(u)
+			if id == "" {
+				return nil, fmt.Errorf("reading %s.json: resource %d has no valid url", name, i)
+			}
+
+			r := resource{raw: raw}
+			for _, f := range searchFields[name] {
+				s, _ := fields[f].(string)
+				r.search = append(r.search, strings.ToLower(s))
+			}
+
+			k.byID[id] = len(k.resources)
+			k.resources = append(k.resources, r)
+		}
+	}
+
+	if !found {
+		return nil, errors.New("no resource files found")
+	}
+
+	return d, nil
+}
+
+// resourceID returns the last segment of the path of a resource URL, such as "1" for
+// https://swapi.dev/api/films/1/.
+func resourceID(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+
+	path := strings.Trim(parsed.Path, "/")
+
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// RoundTrip serves a request for a resource, such as GET /api/films/1/, or a search, such as
+// GET /api/films?search=hope.
+func (d *Dataset) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		_ = r.Body.Close()
+	}
+
+	if r.Method != http.MethodGet {
+		return d.respond(r, http.StatusMethodNotAllowed, map[string]string{"detail": "Method not allowed"})
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/"), "/")
+
+	k, ok := d.kinds[parts[0]]
+	if !ok || len(parts) > 2 {
+		return d.respond(r, http.StatusNotFound, map[string]string{"detail": "Not found"})
+	}
+
+	if len(parts) == 2 {
+		i, ok := k.byID[parts[1]]
+		if !ok {
+			return d.respond(r, http.StatusNotFound, map[string]string{"detail": "Not found"})
+		}
+
+		return d.respond(r, http.StatusOK, k.resources[i].raw)
+	}
+
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	results := []json.RawMessage{}
+
+	for _, res := range k.resources {
+		if res.matches(search) {
+			results = append(results, res.raw)
+		}
+	}
+
+	return d.respond(r, http.StatusOK, map[string]interface{}{
+		"count":    len(results),
+		"next":     nil,
+		"previous": nil,
+		"results":  results,
+	})
+}
+
+func (r resource) matches(search string) bool {
+	for _, s := range r.search {
+		if strings.Contains(s, search) {
+			return true
+		}
+	}
+
+	return search == ""
+}
+
+func (d *Dataset) respond(r *http.Request, status int, v interface{}) (*http.Response, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       r,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package swapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/swapi"
+)
+
+var dataset = fstest.MapFS{
+	"films.json": {Data: []byte(`[
+		{"title": "A New Hope", "episode_id": 4, "url": "https://swapi.dev/api/films/1/",
+			"starships": ["https://swapi.dev/api/starships/9/", "https://swapi.dev/api/starships/10/"]},
+		{"title": "The Empire Strikes Back", "episode_id": 5, "url": "https://swapi.dev/api/films/2/"}
+	]`)},
+	"starships.json": {Data: []byte(`[
+		{"name": "Death Star", "model": "DS-1 Orbital Battle Station", "url": "https://swapi.dev/api/starships/9/"},
+		{"name": "Millennium Falcon", "model": "YT-1300 light freighter", "url": "https://swapi.dev/api/starships/10/"}
+	]`)},
+}
+
+func TestDataset(t *testing.T) {
+	d, err := swapi.LoadDataset(dataset)
+	require.NoError(t, err)
+
+	c := swapi.NewClient(&http.Client{Transport: d})
+	ctx := context.Background()
+
+	film, err := c.Film(ctx, "https://swapi.dev/api/films/1/")
+	require.NoError(t, err)
+	require.Equal(t, "A New Hope", film.Title)
+	require.Len(t, film.StarshipURLs, 2)
+
+	// Resources are found by their URLs, whatever the host.
+	film, err = c.Film(ctx, "https://swapi.example.org/api/films/2/")
+	require.NoError(t, err)
+	require.EqualValues(t, 5, film.EpisodeID)
+
+	_, err = c.Person(ctx, "https://swapi.dev/api/people/1/")
+
+	var status *swapi.StatusError
+	require.ErrorAs(t, err, &status)
+	require.True(t, status.NotFound())
+
+	films, err := c.SearchFilms(ctx, "")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, films.Count)
+
+	films, err = c.SearchFilms(ctx, "EMPIRE")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://swapi.dev/api/films/2/"}, films.URLs())
+
+	// Starships are searched by name or model.
+	starships, err := c.SearchStarships(ctx, "freighter")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://swapi.dev/api/starships/10/"}, starships.URLs())
+}
+
+func TestLoadDatasetInvalid(t *testing.T) {
+	_, err := swapi.LoadDataset(fstest.MapFS{})
+	require.Error(t, err)
+
+	_, err = swapi.LoadDataset(fstest.MapFS{"films.json": {Data: []byte(`[{"title": "No URL"}]`)}})
+	require.EqualError(t, err, "reading films.json: resource 0 has no valid url")
+}
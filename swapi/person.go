@@ -55,12 +55,12 @@ func (c *Client) SearchPerson(ctx context.Context, name string) (PersonPage, err
 	q := url.Values{"search": {name}}
 	r, err := c.NewRequest(ctx, "/people?"+q.Encode())
 	if err != nil {
-		return PersonPage{}, nil
+		return PersonPage{}, err
 	}
 
 	var pp PersonPage
 	if _, err := c.Do(r, &pp); err != nil {
-		return PersonPage{}, nil
+		return PersonPage{}, err
 	}
 
 	return pp, nil
@@ -2,6 +2,7 @@ package swapi
 
 import (
 	"context"
+	"net/url"
 )
 
 type Planet struct {
@@ -35,11 +36,30 @@ func (p PlanetPage) URLs() []string {
 }
 
 func (c *Client) Planet(ctx context.Context, url string) (Planet, error) {
-	// TODO: implement
-	return Planet{}, nil
+	r, err := c.NewRequest(ctx, url)
+	if err != nil {
+		return Planet{}, err
+	}
+
+	var p Planet
+	if _, err := c.Do(r, &p); err != nil {
+		return Planet{}, err
+	}
+
+	return p, nil
 }
 
 func (c *Client) SearchPlanets(ctx context.Context, name string) (PlanetPage, error) {
-	// TODO: implement
-	return PlanetPage{}, nil
+	q := url.Values{"search": {name}}
+	r, err := c.NewRequest(ctx, "/planets?"+q.Encode())
+	if err != nil {
+		return PlanetPage{}, err
+	}
+
+	var pp PlanetPage
+	if _, err := c.Do(r, &pp); err != nil {
+		return PlanetPage{}, err
+	}
+
+	return pp, nil
 }
@@ -27,6 +27,7 @@ type Species struct {
 // SpeciesPage ...
 type SpeciesPage struct {
 	Count   int64     `json:"count"`
+	Next    string    `json:"next"` // URL of the next page, empty on the last page.
 	Species []Species `json:"results"`
 }
 
@@ -53,6 +54,28 @@ func (c *Client) Species(ctx context.Context, url string) (Species, error) {
 	return s, nil
 }
 
+// AllSpecies fetches every species, following the pagination links until the last page.
+func (c *Client) AllSpecies(ctx context.Context) ([]Species, error) {
+	var species []Species
+
+	for next := "/species/"; next != ""; {
+		r, err := c.NewRequest(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+
+		var sp SpeciesPage
+		if _, err := c.Do(r, &sp); err != nil {
+			return nil, err
+		}
+
+		species = append(species, sp.Species...)
+		next = sp.Next
+	}
+
+	return species, nil
+}
+
 // SearchSpecies ...
 func (c *Client) SearchSpecies(ctx context.Context, name string) (SpeciesPage, error) {
 	q := url.Values{"search": {name}}
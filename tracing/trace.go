@@ -0,0 +1,238 @@
+// Package tracing records why an operation took as long as it did: how long each resolver ran, in
+// the Apollo tracing format, and which dataloader batches and SWAPI calls the operation made.
+//
+// A Trace is attached to the context of an operation which should be traced. The Tracer records
+// the operation's resolvers, the loader package records its batches, and the Transport records
+// its SWAPI calls. Operations without a Trace are not recorded.
+package tracing
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The key type is unexported so the trace does not collide with context values set by other
+// packages.
+type key struct{}
+
+// batchKey places the batch being loaded on the context, so calls can be attributed to it.
+type batchKey struct{}
+
+// A Trace records a single operation. It is safe for concurrent use.
+type Trace struct {
+	start time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	validation span
+	resolvers  []resolver
+	loaders    map[string]*loader
+	calls      []*Call
+}
+
+// span is a period of time, as offsets from the start of the trace.
+type span struct {
+	start    time.Duration
+	duration time.Duration
+}
+
+type resolver struct {
+	span
+	path       []interface{}
+	parentType string
+	fieldName  string
+	returnType string
+}
+
+// loader records the use of a single dataloader.
+type loader struct {
+	loads   int
+	batches []*Batch
+}
+
+// A Batch is a batch of keys dispatched by a dataloader.
+type Batch struct {
+	Keys        []string      `json:"keys"`
+	Size        int           `json:"size"`
+	StartOffset time.Duration `json:"startOffset"`
+	Duration    time.Duration `json:"duration"`
+	// Calls are the SWAPI calls made to load the batch.
+	Calls []*Call `json:"calls"`
+}
+
+// A Call is an HTTP call to SWAPI.
+type Call struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Status is the response's status code, or zero when no response was received.
+	Status      int           `json:"status"`
+	Error       string        `json:"error,omitempty"`
+	StartOffset time.Duration `json:"startOffset"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// Attach places a new Trace on the context. The trace starts now.
+func Attach(ctx context.Context) (context.Context, *Trace) {
+	t := &Trace{start: time.Now(), loaders: map[string]*loader{}}
+	return context.WithValue(ctx, key{}, t), t
+}
+
+func fromContext(ctx context.Context) (*Trace, bool) {
+	t, ok := ctx.Value(key{}).(*Trace)
+	return t, ok
+}
+
+// since returns the offset of the time from the start of the trace.
+func (t *Trace) since(at time.Time) time.Duration {
+	return at.Sub(t.start)
+}
+
+// Finish ends the trace.
+func (t *Trace) Finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.end = time.Now()
+}
+
+// RecordLoad records that the named loader was asked for a key. Loads which are not dispatched in
+// a batch were answered from the loader's cache.
+func RecordLoad(ctx context.Context, name string) {
+	t, ok := fromContext(ctx)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.loader(name).loads++
+}
+
+// StartBatch records a batch of keys dispatched by the named loader. The returned context
+// attributes the calls made with it to the batch. The returned function ends the batch.
+func StartBatch(ctx context.Context, name string, keys []string) (context.Context, func()) {
+	t, ok := fromContext(ctx)
+	if !ok {
+		return ctx, func() {}
+	}
+
+	b := &Batch{Keys: keys, Size: len(keys), StartOffset: t.since(time.Now()), Calls: []*Call{}}
+
+	t.mu.Lock()
+	t.loader(name).batches = append(t.loader(name).batches, b)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, batchKey{}, b), func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		b.Duration = t.since(time.Now()) - b.StartOffset
+	}
+}
+
+// loader returns the record of the named loader. The trace must be locked.
+func (t *Trace) loader(name string) *loader {
+	l, ok := t.loaders[name]
+	if !ok {
+		l = &loader{}
+		t.loaders[name] = l
+	}
+
+	return l
+}
+
+// recordCall records a call, in the batch on the context when there is one.
+func (t *Trace) recordCall(ctx context.Context, c *Call) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if b, ok := ctx.Value(batchKey{}).(*Batch); ok {
+		b.Calls = append(b.Calls, c)
+		return
+	}
+
+	t.calls = append(t.calls, c)
+}
+
+// Extensions returns the trace as response extensions: "tracing" in the Apollo tracing format
+// (https://github.com/apollographql/apollo-tracing), and "debug" with the operation's loader
+// batches and SWAPI calls.
+func (t *Trace) Extensions() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return map[string]interface{}{"tracing": t.apollo(), "debug": t.debug()}
+}
+
+func (t *Trace) apollo() map[string]interface{} {
+	resolvers := make([]map[string]interface{}, len(t.resolvers))
+
+	for i, r := range t.resolvers {
+		resolvers[i] = map[string]interface{}{
+			"path":        r.path,
+			"parentType":  r.parentType,
+			"fieldName":   r.fieldName,
+			"returnType":  r.returnType,
+			"startOffset": r.start,
+			"duration":    r.duration,
+		}
+	}
+
+	// Parsing ends when validation starts, since graphql-go does not trace it.
+	return map[string]interface{}{
+		"version":    1,
+		"startTime":  t.start.UTC().Format(time.RFC3339Nano),
+		"endTime":    t.end.UTC().Format(time.RFC3339Nano),
+		"duration":   t.end.Sub(t.start),
+		"parsing":    map[string]interface{}{"startOffset": 0, "duration": t.validation.start},
+		"validation": map[string]interface{}{"startOffset": t.validation.start, "duration": t.validation.duration},
+		"execution":  map[string]interface{}{"resolvers": resolvers},
+	}
+}
+
+func (t *Trace) debug() map[string]interface{} {
+	names := make([]string, 0, len(t.loaders))
+	for name := range t.loaders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	loaders := make([]map[string]interface{}, len(names))
+
+	for i, name := range names {
+		l := t.loaders[name]
+
+		// Batches are copied, since calls may still be recorded on them once the trace has been
+		// returned.
+		batches := make([]Batch, len(l.batches))
+		batched := 0
+
+		for j, b := range l.batches {
+			batches[j] = *b
+			batches[j].Calls = append([]*Call{}, b.Calls...)
+			batched += b.Size
+		}
+
+		hits := l.loads - batched
+		if hits < 0 {
+			// A batch dispatched for this operation may include keys which other operations of
+			// the same request asked for.
+			hits = 0
+		}
+
+		loaders[i] = map[string]interface{}{
+			"loader":    name,
+			"loads":     l.loads,
+			"cacheHits": hits,
+			"batches":   batches,
+		}
+	}
+
+	calls := append([]*Call{}, t.calls...)
+
+	return map[string]interface{}{"loaders": loaders, "calls": calls}
+}
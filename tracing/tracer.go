@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/introspection"
+	"github.com/graph-gophers/graphql-go/trace"
+)
+
+// Types holds the return types of the fields of a schema, which graphql-go does not pass to
+// tracers.
+type Types struct {
+	fields map[string]string
+}
+
+// NewTypes reads the return types of the fields of the schema.
+func NewTypes(sdl string) (*Types, error) {
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	if err != nil {
+		return nil, err
+	}
+
+	all := &struct{ IncludeDeprecated bool }{true}
+	types := &Types{fields: map[string]string{}}
+
+	for _, t := range s.Inspect().Types() {
+		if fields := t.Fields(all); fields != nil {
+			for _, f := range *fields {
+				types.fields[*t.Name()+"."+f.Name()] = typeString(f.Type())
+			}
+		}
+	}
+
+	return types, nil
+}
+
+// typeString writes the type as it appears in SDL, such as "[Film!]".
+func typeString(t *introspection.Type) string {
+	switch t.Kind() {
+	case "NON_NULL":
+		return typeString(t.OfType()) + "!"
+	case "LIST":
+		return "[" + typeString(t.OfType()) + "]"
+	}
+
+	return *t.Name()
+}
+
+// A Tracer records the resolvers of operations with a Trace on their context, then hands over to
+// the wrapped Tracer.
+//
+// graphql-go does not pass the paths of fields to tracers, so they are reconstructed from field
+// names. Neither the index of a list item nor the alias of a field is known, so the paths of the
+// fields selected on the items of a list leave out the index, and paths use field names.
+type Tracer struct {
+	trace.Tracer
+	Types *Types
+}
+
+// The fieldKey type places the path of the field being resolved on the context, so the fields
+// selected on its value know their parent.
+type fieldKey struct{}
+
+type field struct {
+	path []interface{}
+}
+
+// TraceField records the time the field takes to resolve.
+func (t Tracer) TraceField(ctx context.Context, label, typeName, fieldName string, trivial bool, args map[string]interface{}) (context.Context, trace.TraceFieldFinishFunc) {
+	tr, ok := fromContext(ctx)
+	if !ok {
+		return t.Tracer.TraceField(ctx, label, typeName, fieldName, trivial, args)
+	}
+
+	start := time.Now()
+	rt := t.Types.fields[typeName+"."+fieldName]
+
+	f := &field{path: childPath(ctx, fieldName)}
+
+	ctx, finish := t.Tracer.TraceField(ctx, label, typeName, fieldName, trivial, args)
+
+	return context.WithValue(ctx, fieldKey{}, f), func(err *errors.QueryError) {
+		finish(err)
+
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+
+		tr.resolvers = append(tr.resolvers, resolver{
+			span:       span{start: tr.since(start), duration: time.Since(start)},
+			path:       f.path,
+			parentType: typeName,
+			fieldName:  fieldName,
+			returnType: rt,
+		})
+	}
+}
+
+// childPath returns the path of the field selected on the value of the field on the context.
+func childPath(ctx context.Context, name string) []interface{} {
+	parent, ok := ctx.Value(fieldKey{}).(*field)
+	if !ok {
+		return []interface{}{name}
+	}
+
+	return append(append([]interface{}{}, parent.path...), name)
+}
+
+// TraceValidation records the time validation takes, and hands over to the wrapped Tracer when it
+// traces validation.
+func (t Tracer) TraceValidation(ctx context.Context) trace.TraceValidationFinishFunc {
+	finish := func([]*errors.QueryError) {}
+	if v, ok := t.Tracer.(trace.ValidationTracerContext); ok {
+		finish = v.TraceValidation(ctx)
+	}
+
+	tr, ok := fromContext(ctx)
+	if !ok {
+		return finish
+	}
+
+	start := time.Now()
+
+	return func(errs []*errors.QueryError) {
+		finish(errs)
+
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+
+		tr.validation = span{start: tr.since(start), duration: time.Since(start)}
+	}
+}
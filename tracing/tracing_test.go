@@ -0,0 +1,133 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/loader"
+	"github.com/tonyghita/graphql-go-example/resolver"
+	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/swapi"
+	"github.com/tonyghita/graphql-go-example/tracing"
+)
+
+// filmWithCharacters serves a film with two characters, and the characters.
+type filmWithCharacters struct{}
+
+func (filmWithCharacters) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := `{"count": 1, "results": [{"title": "A New Hope", "url": "https://swapi.dev/api/films/1/",
+		"characters": ["https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/"]}]}`
+
+	if strings.HasPrefix(r.URL.Path, "/api/people/") {
+		body = fmt.Sprintf(`{"name": "Person %s", "url": "%s"}`, strings.Trim(r.URL.Path, "/api/people/"), r.URL)
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestTrace(t *testing.T) {
+	s, err := schema.String()
+	require.NoError(t, err)
+
+	types, err := tracing.NewTypes(s)
+	require.NoError(t, err)
+
+	c := swapi.NewClient(&http.Client{Transport: tracing.Transport(filmWithCharacters{})})
+
+	root, err := resolver.NewRoot(c)
+	require.NoError(t, err)
+
+	tracer := tracing.Tracer{Tracer: trace.OpenTracingTracer{}, Types: types}
+	exec := graphql.MustParseSchema(s, root, graphql.UseStringDescriptions(), graphql.Tracer(tracer))
+
+	ctx := loader.Initialize(c, loader.Settings{}).Attach(context.Background())
+	ctx, tr := tracing.Attach(ctx)
+
+	res := exec.Exec(ctx, `{ films { title characters { name } } }`, "", nil)
+	require.Empty(t, res.Errors)
+
+	tr.Finish()
+
+	b, err := json.Marshal(tr.Extensions())
+	require.NoError(t, err)
+
+	var ext struct {
+		Tracing struct {
+			Version    int
+			Duration   int64
+			Validation struct{ Duration int64 }
+			Execution  struct {
+				Resolvers []struct {
+					Path       []interface{}
+					ParentType string
+					FieldName  string
+					ReturnType string
+				}
+			}
+		}
+		Debug struct {
+			Loaders []struct {
+				Loader    string
+				Loads     int
+				CacheHits int
+				Batches   []struct {
+					Keys  []string
+					Size  int
+					Calls []struct{ URL string }
+				}
+			}
+			Calls []struct {
+				Method string
+				URL    string
+				Status int
+			}
+		}
+	}
+
+	require.NoError(t, json.Unmarshal(b, &ext))
+
+	require.Equal(t, 1, ext.Tracing.Version)
+	require.Positive(t, ext.Tracing.Duration)
+	require.Positive(t, ext.Tracing.Validation.Duration)
+
+	var resolvers []string
+	for _, r := range ext.Tracing.Execution.Resolvers {
+		p, err := json.Marshal(r.Path)
+		require.NoError(t, err)
+
+		resolvers = append(resolvers, string(p)+" "+r.ParentType+"."+r.FieldName+": "+r.ReturnType)
+	}
+
+	// List items resolve concurrently, and tracers are not told their index.
+	require.ElementsMatch(t, []string{
+		`["films"] Query.films: [Film!]`,
+		`["films","title"] Film.title: String!`,
+		`["films","characters"] Film.characters: [Person]`,
+		`["films","characters","name"] Person.name: String!`,
+		`["films","characters","name"] Person.name: String!`,
+	}, resolvers)
+
+	// The search is made by a resolver, outside any batch.
+	require.Len(t, ext.Debug.Calls, 1)
+	require.Equal(t, "GET", ext.Debug.Calls[0].Method)
+	require.Equal(t, http.StatusOK, ext.Debug.Calls[0].Status)
+
+	require.Len(t, ext.Debug.Loaders, 1)
+	person := ext.Debug.Loaders[0]
+	require.Equal(t, "person", person.Loader)
+	require.Equal(t, 2, person.Loads)
+	require.Zero(t, person.CacheHits)
+	require.Len(t, person.Batches, 1)
+	require.Equal(t, 2, person.Batches[0].Size)
+	require.ElementsMatch(t, []string{"https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/"}, person.Batches[0].Keys)
+	require.Len(t, person.Batches[0].Calls, 2)
+}
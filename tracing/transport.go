@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"net/http"
+	"time"
+)
+
+// Transport returns an http.RoundTripper which records every request sent through the base
+// RoundTripper on the Trace of the request's context, as part of the batch on the context when
+// there is one. The call is timed until the response headers are received.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	tr, ok := fromContext(r.Context())
+	if !ok {
+		return t.base.RoundTrip(r)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(r)
+
+	c := &Call{Method: r.Method, URL: r.URL.String(), StartOffset: tr.since(start), Duration: time.Since(start)}
+
+	if err != nil {
+		c.Error = err.Error()
+	} else {
+		c.Status = resp.StatusCode
+	}
+
+	tr.recordCall(r.Context(), c)
+
+	return resp, err
+}
@@ -0,0 +1,42 @@
+package units
+
+// A Length is a unit of linear dimension.
+type Length int
+
+const (
+	Millimeter Length = iota
+	Centimeter
+	Meter
+	Kilometer
+	Inch
+	Foot
+	Yard
+	Mile
+	AstronomicalUnit
+	LightYear
+	Parsec
+)
+
+var lengths = newTable("LengthUnit", []unit{
+	Millimeter:       {"MILLIMETER", 1e-3},
+	Centimeter:       {"CENTIMETER", 1e-2},
+	Meter:            {"METER", 1e0},
+	Kilometer:        {"KILOMETER", 1e3},
+	Inch:             {"INCH", 0.0254},
+	Foot:             {"FOOT", 0.0254 * 12},
+	Yard:             {"YARD", 0.0254 * 36},
+	Mile:             {"MILE", 1609.344},
+	AstronomicalUnit: {"ASTRONOMICAL_UNIT", 149597870700},
+	LightYear:        {"LIGHT_YEAR", 9460730472580800},
+	Parsec:           {"PARSEC", 3.0856775814913673e16},
+})
+
+// Lengths returns the names of every Length, in declaration order.
+func Lengths() []string {
+	return lengths.names()
+}
+
+// String returns the LengthUnit enumeration value of the Length.
+func (u Length) String() string {
+	return lengths.name(int(u))
+}
@@ -0,0 +1,28 @@
+package units
+
+// A Mass is a unit of the amount of matter in a physical body.
+type Mass int
+
+const (
+	Gram Mass = iota
+	Kilogram
+	MetricTon
+	Pound
+)
+
+var masses = newTable("MassUnit", []unit{
+	Gram:      {"GRAM", 1e-3},
+	Kilogram:  {"KILOGRAM", 1e0},
+	MetricTon: {"METRIC_TON", 1e3},
+	Pound:     {"POUND", 0.45359237},
+})
+
+// Masses returns the names of every Mass, in declaration order.
+func Masses() []string {
+	return masses.names()
+}
+
+// String returns the MassUnit enumeration value of the Mass.
+func (u Mass) String() string {
+	return masses.name(int(u))
+}
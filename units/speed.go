@@ -0,0 +1,28 @@
+package units
+
+// A Speed is a unit of distance travelled per unit of time.
+type Speed int
+
+const (
+	KilometersPerHour Speed = iota
+	MilesPerHour
+	MetersPerSecond
+	Knot
+)
+
+var speeds = newTable("SpeedUnit", []unit{
+	KilometersPerHour: {"KILOMETERS_PER_HOUR", 1e0},
+	MilesPerHour:      {"MILES_PER_HOUR", 1.609344},
+	MetersPerSecond:   {"METERS_PER_SECOND", 3.6},
+	Knot:              {"KNOT", 1.852},
+})
+
+// Speeds returns the names of every Speed, in declaration order.
+func Speeds() []string {
+	return speeds.names()
+}
+
+// String returns the SpeedUnit enumeration value of the Speed.
+func (u Speed) String() string {
+	return speeds.name(int(u))
+}
@@ -0,0 +1,32 @@
+package units
+
+// A Time is a unit of duration.
+type Time int
+
+const (
+	Hour Time = iota
+	Day
+	Week
+	Month
+	Year
+	StandardGalacticYear
+)
+
+var times = newTable("TimeUnit", []unit{
+	Hour:                 {"HOUR", 1},
+	Day:                  {"DAY", 24},
+	Week:                 {"WEEK", 24 * 7},
+	Month:                {"MONTH", 24 * 365.25 / 12},
+	Year:                 {"YEAR", 24 * 365.25},
+	StandardGalacticYear: {"STANDARD_GALACTIC_YEAR", 24 * 368},
+})
+
+// Times returns the names of every Time, in declaration order.
+func Times() []string {
+	return times.names()
+}
+
+// String returns the TimeUnit enumeration value of the Time.
+func (u Time) String() string {
+	return times.name(int(u))
+}
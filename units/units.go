@@ -0,0 +1,103 @@
+// Package units defines the units of measure that dimensional fields can be expressed in, and the
+// conversions between them.
+//
+// Each dimension (length, mass, speed and time) is its own type, so a length can never be
+// converted into a mass by mistake. The name of every unit is the GraphQL enum value that
+// represents it, and parsing a unit's name always returns that same unit.
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A unit describes a single unit of measure within a dimension.
+type unit struct {
+	// name is the GraphQL enum value for the unit, such as "KILOMETER".
+	name string
+	// factor is the size of the unit relative to the base unit of its dimension.
+	factor float64
+}
+
+// A Unit is a unit of measure of any dimension.
+type Unit interface {
+	Length | Mass | Speed | Time
+}
+
+// Parse converts an enumeration value to a unit of the dimension U, such as Parse[Length]("METER").
+func Parse[U Unit](s string) (U, error) {
+	i, err := tableOf[U]().parse(s)
+	return U(i), err
+}
+
+// Convert converts a value measured in one unit to another unit of the same dimension.
+func Convert[U Unit](v float64, from, to U) float64 {
+	return tableOf[U]().convert(v, int(from), int(to))
+}
+
+// tableOf returns the table of the units of the dimension U.
+func tableOf[U Unit]() *table {
+	var u U
+
+	switch any(u).(type) {
+	case Length:
+		return lengths
+	case Mass:
+		return masses
+	case Speed:
+		return speeds
+	default:
+		return times
+	}
+}
+
+// A table holds all the units of a dimension, indexed by their enumeration value.
+type table struct {
+	enum  string
+	units []unit
+	index map[string]int
+}
+
+func newTable(enum string, units []unit) *table {
+	t := &table{enum: enum, units: units, index: make(map[string]int, len(units))}
+
+	for i, u := range units {
+		if _, ok := t.index[u.name]; ok {
+			panic(fmt.Sprintf("units: duplicate %s value %s", enum, u.name))
+		}
+
+		t.index[u.name] = i
+	}
+
+	return t
+}
+
+func (t *table) parse(s string) (int, error) {
+	i, ok := t.index[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("unknown %s: %s", t.enum, s)
+	}
+
+	return i, nil
+}
+
+func (t *table) name(i int) string {
+	if i < 0 || i >= len(t.units) {
+		return ""
+	}
+
+	return t.units[i].name
+}
+
+func (t *table) names() []string {
+	names := make([]string, len(t.units))
+	for i, u := range t.units {
+		names[i] = u.name
+	}
+
+	return names
+}
+
+func (t *table) convert(v float64, from, to int) float64 {
+	return (v * t.units[from].factor) / t.units[to].factor
+}
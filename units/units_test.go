@@ -0,0 +1,97 @@
+package units_test
+
+import (
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/schema"
+	"github.com/tonyghita/graphql-go-example/units"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, name := range units.Lengths() {
+		u, err := units.Parse[units.Length](name)
+		require.NoError(t, err)
+		require.Equal(t, name, u.String())
+	}
+
+	for _, name := range units.Masses() {
+		u, err := units.Parse[units.Mass](name)
+		require.NoError(t, err)
+		require.Equal(t, name, u.String())
+	}
+
+	for _, name := range units.Speeds() {
+		u, err := units.Parse[units.Speed](name)
+		require.NoError(t, err)
+		require.Equal(t, name, u.String())
+	}
+
+	for _, name := range units.Times() {
+		u, err := units.Parse[units.Time](name)
+		require.NoError(t, err)
+		require.Equal(t, name, u.String())
+	}
+
+	_, err := units.Parse[units.Length]("FURLONG")
+	require.EqualError(t, err, "unknown LengthUnit: FURLONG")
+}
+
+func TestEnumsMatchSchema(t *testing.T) {
+	s, err := schema.String()
+	require.NoError(t, err)
+
+	enums := map[string][]string{
+		"LengthUnit": units.Lengths(),
+		"MassUnit":   units.Masses(),
+		"SpeedUnit":  units.Speeds(),
+		"TimeUnit":   units.Times(),
+	}
+
+	for _, typ := range graphql.MustParseSchema(s, nil, graphql.UseStringDescriptions()).Inspect().Types() {
+		expected, ok := enums[*typ.Name()]
+		if !ok {
+			continue
+		}
+
+		var actual []string
+		for _, v := range *typ.EnumValues(&struct{ IncludeDeprecated bool }{true}) {
+			actual = append(actual, v.Name())
+		}
+
+		require.ElementsMatch(t, expected, actual, "enum %s", *typ.Name())
+		delete(enums, *typ.Name())
+	}
+
+	require.Empty(t, enums, "enums missing from the schema")
+}
+
+func TestConvert(t *testing.T) {
+	t.Run("Length", func(t *testing.T) {
+		v := units.Convert(172, units.Centimeter, units.Meter)
+		require.InDelta(t, 1.72, v, 1e-9)
+
+		v = units.Convert(1, units.Parsec, units.LightYear)
+		require.InDelta(t, 3.2616, v, 1e-4)
+
+		v = units.Convert(1, units.Mile, units.Foot)
+		require.InDelta(t, 5280, v, 1e-9)
+	})
+
+	t.Run("Mass", func(t *testing.T) {
+		v := units.Convert(1, units.MetricTon, units.Gram)
+		require.InDelta(t, 1e6, v, 1e-6)
+	})
+
+	t.Run("Speed", func(t *testing.T) {
+		v := units.Convert(1000, units.KilometersPerHour, units.MilesPerHour)
+		require.InDelta(t, 621.371, v, 1e-3)
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		v := units.Convert(1, units.StandardGalacticYear, units.Day)
+		require.InDelta(t, 368, v, 1e-9)
+	})
+}
@@ -0,0 +1,203 @@
+// Package usage records which parts of the schema each client uses, so deprecated fields can be
+// removed once no client queries them any more.
+//
+// Usage is tracked by schema coordinate: "Type.field" for fields and "Enum.VALUE" for enum values
+// written as literals in a query.
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/introspection"
+
+	"github.com/tonyghita/graphql-go-example/language"
+)
+
+// An Index describes the coordinates of a schema.
+type Index struct {
+	coordinates map[string]*coordinate
+	query       string
+}
+
+type coordinate struct {
+	deprecated bool
+	reason     string
+	// typ is the name of the type a field returns, unwrapped from lists and non-null types.
+	typ string
+	// args maps the field's argument names to the names of their types.
+	args map[string]string
+}
+
+// NewIndex reads the coordinates of the schema.
+func NewIndex(sdl string) (*Index, error) {
+	s, err := graphql.ParseSchema(sdl, nil, graphql.UseStringDescriptions())
+	if err != nil {
+		return nil, err
+	}
+
+	inspected := s.Inspect()
+	all := &struct{ IncludeDeprecated bool }{true}
+
+	idx := &Index{
+		coordinates: map[string]*coordinate{},
+		query:       *inspected.QueryType().Name(),
+	}
+
+	for _, t := range inspected.Types() {
+		name := *t.Name()
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+
+		if fields := t.Fields(all); fields != nil {
+			for _, f := range *fields {
+				c := &coordinate{deprecated: f.IsDeprecated(), typ: named(f.Type()), args: map[string]string{}}
+				if r := f.DeprecationReason(); r != nil {
+					c.reason = *r
+				}
+
+				for _, a := range f.Args() {
+					c.args[a.Name()] = named(a.Type())
+				}
+
+				idx.coordinates[name+"."+f.Name()] = c
+			}
+		}
+
+		if values := t.EnumValues(all); values != nil {
+			for _, v := range *values {
+				c := &coordinate{deprecated: v.IsDeprecated()}
+				if r := v.DeprecationReason(); r != nil {
+					c.reason = *r
+				}
+
+				idx.coordinates[name+"."+v.Name()] = c
+			}
+		}
+	}
+
+	return idx, nil
+}
+
+// Deprecated reports whether the coordinate is deprecated, and why.
+func (idx *Index) Deprecated(coordinate string) (bool, string) {
+	c, ok := idx.coordinates[coordinate]
+	if !ok {
+		return false, ""
+	}
+
+	return c.deprecated, c.reason
+}
+
+// Coordinates returns the sorted coordinates an operation uses.
+// Enum values passed in variables are not known until execution, so they are not included.
+func (idx *Index) Coordinates(query, opName string) ([]string, error) {
+	doc, err := language.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	op := doc.Operation(opName)
+	if op == nil {
+		return nil, fmt.Errorf("no operation named %q", opName)
+	}
+
+	if op.Type != "query" {
+		return nil, fmt.Errorf("unsupported operation type %q", op.Type)
+	}
+
+	w := &walker{idx: idx, doc: doc, used: map[string]bool{}, spread: map[string]bool{}}
+	w.selections(idx.query, op.SelectionSet)
+
+	coords := make([]string, 0, len(w.used))
+	for c := range w.used {
+		coords = append(coords, c)
+	}
+
+	sort.Strings(coords)
+
+	return coords, nil
+}
+
+type walker struct {
+	idx  *Index
+	doc  *language.Document
+	used map[string]bool
+	// spread holds the fragments already walked, since a fragment uses the same coordinates
+	// wherever it is spread.
+	spread map[string]bool
+}
+
+func (w *walker) selections(typ string, sels []language.Selection) {
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *language.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+
+			name := typ + "." + sel.Name
+
+			c, ok := w.idx.coordinates[name]
+			if !ok {
+				continue
+			}
+
+			w.used[name] = true
+
+			for _, a := range sel.Arguments {
+				w.value(c.args[a.Name], a.Value)
+			}
+
+			w.selections(c.typ, sel.SelectionSet)
+		case *language.InlineFragment:
+			cond := sel.TypeCondition
+			if cond == "" {
+				cond = typ
+			}
+
+			w.selections(cond, sel.SelectionSet)
+		case *language.FragmentSpread:
+			if w.spread[sel.Name] {
+				continue
+			}
+
+			w.spread[sel.Name] = true
+
+			if f := w.doc.Fragment(sel.Name); f != nil {
+				w.selections(f.TypeCondition, f.SelectionSet)
+			}
+		}
+	}
+}
+
+// value records the enum values written in an argument value of the named type.
+func (w *walker) value(typ string, v *language.Value) {
+	if v == nil {
+		return
+	}
+
+	switch v.Kind {
+	case language.EnumValue:
+		name := typ + "." + v.Text
+		if _, ok := w.idx.coordinates[name]; ok {
+			w.used[name] = true
+		}
+	case language.ListValue:
+		for _, item := range v.List {
+			w.value(typ, item)
+		}
+	}
+}
+
+// named returns the name of a type, unwrapped from lists and non-null types.
+func named(t *introspection.Type) string {
+	for t.Name() == nil {
+		t = t.OfType()
+	}
+
+	return *t.Name()
+}
@@ -0,0 +1,353 @@
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Client identifies the application which sent an operation.
+type Client struct {
+	Name    string
+	Version string
+}
+
+// maxClientLength bounds the length of client names and versions, which are supplied by clients.
+const maxClientLength = 64
+
+// UnknownClient names clients which do not identify themselves, and clients beyond the number a
+// Tracker counts separately.
+const UnknownClient = "unknown"
+
+// A Tracker counts how often each client uses each coordinate, per day.
+// It is safe for concurrent use.
+type Tracker struct {
+	index      *Index
+	path       string
+	retention  int
+	maxClients int
+
+	mu      sync.Mutex
+	entries map[entryKey]*entry
+	// clients maps each client with counts to its number of entries.
+	clients map[Client]int
+	dirty   bool
+}
+
+type entryKey struct {
+	coordinate string
+	client     Client
+}
+
+type entry struct {
+	lastSeen time.Time
+	// days maps UTC dates, such as "2021-06-30", to the number of operations which used the
+	// coordinate that day.
+	days map[string]int64
+}
+
+// dayFormat formats the dates counts are kept for.
+const dayFormat = "2006-01-02"
+
+// Open creates a Tracker which persists its counts to the file at path, reading the counts saved
+// there before. When path is empty, counts are only kept in memory. Counts older than retention
+// days are discarded when the Tracker is flushed.
+//
+// Clients name themselves, so the Tracker counts at most maxClients distinct clients; operations
+// of further clients are counted as UnknownClient. A maxClients of zero or less is unlimited.
+func Open(index *Index, path string, retention, maxClients int) (*Tracker, error) {
+	t := &Tracker{
+		index:      index,
+		path:       path,
+		retention:  retention,
+		maxClients: maxClients,
+		entries:    map[entryKey]*entry{},
+		clients:    map[Client]int{},
+	}
+
+	if path == "" {
+		return t, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return t, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+
+	for _, e := range f.Entries {
+		if e.Days == nil {
+			e.Days = map[string]int64{}
+		}
+
+		c := Client{e.Client, e.ClientVersion}
+		t.entries[entryKey{e.Coordinate, c}] = &entry{lastSeen: e.LastSeen, days: e.Days}
+		t.clients[c]++
+	}
+
+	return t, nil
+}
+
+// Record counts the coordinates used by an operation sent by the client.
+// An error is returned when the operation cannot be parsed; nothing is recorded then.
+func (t *Tracker) Record(c Client, query, opName string, now time.Time) error {
+	coords, err := t.index.Coordinates(query, opName)
+	if err != nil {
+		return err
+	}
+
+	c.Name, c.Version = truncate(c.Name), truncate(c.Version)
+	day := now.UTC().Format(dayFormat)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.clients[c]; !ok && t.maxClients > 0 && len(t.clients) >= t.maxClients {
+		c = Client{Name: UnknownClient}
+	}
+
+	for _, coord := range coords {
+		k := entryKey{coord, c}
+
+		e, ok := t.entries[k]
+		if !ok {
+			e = &entry{days: map[string]int64{}}
+			t.entries[k] = e
+			t.clients[c]++
+		}
+
+		e.days[day]++
+		if now.After(e.lastSeen) {
+			e.lastSeen = now
+		}
+	}
+
+	t.dirty = t.dirty || len(coords) > 0
+
+	return nil
+}
+
+// Flush discards expired counts and writes the counts to the Tracker's file, when they changed
+// since the last flush. The file is replaced atomically, so a crash never leaves it half-written.
+func (t *Tracker) Flush(now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.expire(now)
+
+	if t.path == "" || !t.dirty {
+		return nil
+	}
+
+	f := file{Entries: make([]fileEntry, 0, len(t.entries))}
+	for k, e := range t.entries {
+		f.Entries = append(f.Entries, fileEntry{
+			Coordinate:    k.coordinate,
+			Client:        k.client.Name,
+			ClientVersion: k.client.Version,
+			LastSeen:      e.lastSeen,
+			Days:          e.days,
+		})
+	}
+
+	sort.Slice(f.Entries, func(i, j int) bool {
+		a, b := f.Entries[i], f.Entries[j]
+		if a.Coordinate != b.Coordinate {
+			return a.Coordinate < b.Coordinate
+		}
+
+		if a.Client != b.Client {
+			return a.Client < b.Client
+		}
+
+		return a.ClientVersion < b.ClientVersion
+	})
+
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, t.path); err != nil {
+		return err
+	}
+
+	t.dirty = false
+
+	return nil
+}
+
+// expire discards the counts of days outside the retention period, and the entries left without
+// counts. The caller must hold t.mu.
+func (t *Tracker) expire(now time.Time) {
+	if t.retention <= 0 {
+		return
+	}
+
+	oldest := now.UTC().AddDate(0, 0, -t.retention+1).Format(dayFormat)
+
+	for k, e := range t.entries {
+		for day := range e.days {
+			if day < oldest {
+				delete(e.days, day)
+				t.dirty = true
+			}
+		}
+
+		if len(e.days) == 0 {
+			delete(t.entries, k)
+
+			if t.clients[k.client]--; t.clients[k.client] == 0 {
+				delete(t.clients, k.client)
+			}
+		}
+	}
+}
+
+// A Filter selects the coordinates of a report.
+type Filter struct {
+	// Coordinate selects a single coordinate, such as "Film.directorName", or every coordinate of
+	// a type, such as "Film".
+	Coordinate string
+	// Deprecated selects only deprecated coordinates.
+	Deprecated bool
+	// UnusedFor selects only coordinates which no client has used for this many days.
+	UnusedFor int
+	// Days limits counts to the last this many days, including today. When zero, counts of every
+	// retained day are included.
+	Days int
+}
+
+// Usage is the usage of a coordinate.
+type Usage struct {
+	Coordinate        string        `json:"coordinate"`
+	Deprecated        bool          `json:"deprecated"`
+	DeprecationReason string        `json:"deprecationReason,omitempty"`
+	Count             int64         `json:"count"`
+	LastSeen          *time.Time    `json:"lastSeen"`
+	Clients           []ClientUsage `json:"clients"`
+}
+
+// ClientUsage is the usage of a coordinate by a single client.
+type ClientUsage struct {
+	Name     string    `json:"name"`
+	Version  string    `json:"version"`
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Report returns the usage of every coordinate of the schema, and of coordinates which have been
+// removed from the schema but still have counts, sorted by coordinate.
+func (t *Tracker) Report(f Filter, now time.Time) []Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	from := ""
+	if f.Days > 0 {
+		from = now.UTC().AddDate(0, 0, -f.Days+1).Format(dayFormat)
+	}
+
+	usages := map[string]*Usage{}
+
+	get := func(coord string) *Usage {
+		u, ok := usages[coord]
+		if !ok {
+			u = &Usage{Coordinate: coord, Clients: []ClientUsage{}}
+			u.Deprecated, u.DeprecationReason = t.index.Deprecated(coord)
+			usages[coord] = u
+		}
+
+		return u
+	}
+
+	for coord := range t.index.coordinates {
+		get(coord)
+	}
+
+	for k, e := range t.entries {
+		u := get(k.coordinate)
+
+		c := ClientUsage{Name: k.client.Name, Version: k.client.Version, LastSeen: e.lastSeen}
+		for day, n := range e.days {
+			if day >= from {
+				c.Count += n
+			}
+		}
+
+		u.Count += c.Count
+		u.Clients = append(u.Clients, c)
+
+		if u.LastSeen == nil || e.lastSeen.After(*u.LastSeen) {
+			lastSeen := e.lastSeen
+			u.LastSeen = &lastSeen
+		}
+	}
+
+	unusedSince := now.AddDate(0, 0, -f.UnusedFor)
+
+	report := make([]Usage, 0, len(usages))
+	for _, u := range usages {
+		switch {
+		case f.Coordinate != "" && u.Coordinate != f.Coordinate && !strings.HasPrefix(u.Coordinate, f.Coordinate+"."):
+			continue
+		case f.Deprecated && !u.Deprecated:
+			continue
+		case f.UnusedFor > 0 && u.LastSeen != nil && u.LastSeen.After(unusedSince):
+			continue
+		}
+
+		sort.Slice(u.Clients, func(i, j int) bool {
+			a, b := u.Clients[i], u.Clients[j]
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+
+			return a.Version < b.Version
+		})
+
+		report = append(report, *u)
+	}
+
+	sort.Slice(report, func(i, j int) bool { return report[i].Coordinate < report[j].Coordinate })
+
+	return report
+}
+
+// file is the format counts are persisted in.
+type file struct {
+	Entries []fileEntry `json:"entries"`
+}
+
+type fileEntry struct {
+	Coordinate    string           `json:"coordinate"`
+	Client        string           `json:"client"`
+	ClientVersion string           `json:"clientVersion"`
+	LastSeen      time.Time        `json:"lastSeen"`
+	Days          map[string]int64 `json:"days"`
+}
+
+func truncate(s string) string {
+	if len(s) > maxClientLength {
+		return s[:maxClientLength]
+	}
+
+	return s
+}
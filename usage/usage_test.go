@@ -0,0 +1,150 @@
+package usage_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonyghita/graphql-go-example/usage"
+)
+
+const sdl = `
+schema { query: Query }
+
+type Query {
+	films(unit: LengthUnit = METER): [Film]
+	node: Node
+}
+
+interface Node { id: ID! }
+
+type Film implements Node {
+	id: ID!
+	director: String!
+	directorName: String! @deprecated(reason: "Use director.")
+}
+
+enum LengthUnit {
+	METER
+	FOOT @deprecated(reason: "Use METER.")
+}
+`
+
+func newIndex(t *testing.T) *usage.Index {
+	idx, err := usage.NewIndex(sdl)
+	require.NoError(t, err)
+
+	return idx
+}
+
+func TestCoordinates(t *testing.T) {
+	idx := newIndex(t)
+
+	coords, err := idx.Coordinates(`
+		query A { films(unit: FOOT) { ...F } }
+		query B { node { __typename id ... on Film { director } } }
+		fragment F on Film { id directorName ...F2 }
+		fragment F2 on Film { id }
+	`, "A")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Film.directorName", "Film.id", "LengthUnit.FOOT", "Query.films"}, coords)
+
+	coords, err = idx.Coordinates(`query A { films { id } } query B { node { __typename id ... on Film { director } } }`, "B")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Film.director", "Node.id", "Query.node"}, coords)
+
+	_, err = idx.Coordinates(`{ films { id } } { node { id } }`, "")
+	require.Error(t, err)
+}
+
+func TestTracker(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "usage.json")
+		ios  = usage.Client{Name: "ios", Version: "1.0"}
+		web  = usage.Client{Name: "web", Version: "2.3"}
+		day1 = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+		day5 = day1.AddDate(0, 0, 4)
+	)
+
+	tracker, err := usage.Open(newIndex(t), path, 30, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, tracker.Record(ios, `{ films { directorName } }`, "", day1))
+	require.NoError(t, tracker.Record(ios, `{ films { directorName } }`, "", day1))
+	require.NoError(t, tracker.Record(web, `{ films { director } }`, "", day5))
+	require.Error(t, tracker.Record(web, `{ films {`, "", day5))
+	require.NoError(t, tracker.Flush(day5))
+
+	// Counts survive a restart.
+	tracker, err = usage.Open(newIndex(t), path, 30, 0)
+	require.NoError(t, err)
+
+	report := tracker.Report(usage.Filter{Coordinate: "Film.directorName"}, day5)
+	require.Len(t, report, 1)
+	require.True(t, report[0].Deprecated)
+	require.Equal(t, "Use director.", report[0].DeprecationReason)
+	require.EqualValues(t, 2, report[0].Count)
+	require.Equal(t, day1, report[0].LastSeen.UTC())
+	require.Equal(t, []usage.ClientUsage{{Name: "ios", Version: "1.0", Count: 2, LastSeen: day1}}, utc(report[0].Clients))
+
+	// Only the last three days are counted.
+	report = tracker.Report(usage.Filter{Coordinate: "Film.directorName", Days: 3}, day5)
+	require.EqualValues(t, 0, report[0].Count)
+
+	// Deprecated coordinates unused for three days include those never used at all.
+	var unused []string
+	for _, u := range tracker.Report(usage.Filter{Deprecated: true, UnusedFor: 3}, day5) {
+		unused = append(unused, u.Coordinate)
+	}
+	require.Equal(t, []string{"Film.directorName", "LengthUnit.FOOT"}, unused)
+
+	// Counts outside the retention period are discarded.
+	require.NoError(t, tracker.Flush(day1.AddDate(0, 0, 31)))
+
+	report = tracker.Report(usage.Filter{Coordinate: "Film"}, day5)
+	require.Len(t, report, 3)
+
+	for _, u := range report {
+		if u.Coordinate == "Film.director" {
+			require.EqualValues(t, 1, u.Count)
+		} else {
+			require.Nil(t, u.LastSeen, u.Coordinate)
+		}
+	}
+}
+
+func TestTrackerMaxClients(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker, err := usage.Open(newIndex(t), "", 30, 2)
+	require.NoError(t, err)
+
+	for _, name := range []string{"ios", "web", "spoofed-1", "spoofed-2", "ios"} {
+		require.NoError(t, tracker.Record(usage.Client{Name: name}, `{ films { id } }`, "", now))
+	}
+
+	report := tracker.Report(usage.Filter{Coordinate: "Film.id"}, now)
+	require.Len(t, report, 1)
+	require.Equal(t, []usage.ClientUsage{
+		{Name: "ios", Count: 2, LastSeen: now},
+		{Name: "unknown", Count: 2, LastSeen: now},
+		{Name: "web", Count: 1, LastSeen: now},
+	}, utc(report[0].Clients))
+
+	// Expired clients make room for new ones.
+	require.NoError(t, tracker.Flush(now.AddDate(0, 0, 31)))
+	require.NoError(t, tracker.Record(usage.Client{Name: "android"}, `{ films { id } }`, "", now.AddDate(0, 0, 31)))
+
+	report = tracker.Report(usage.Filter{Coordinate: "Film.id"}, now.AddDate(0, 0, 31))
+	require.Equal(t, "android", report[0].Clients[0].Name)
+}
+
+func utc(clients []usage.ClientUsage) []usage.ClientUsage {
+	for i := range clients {
+		clients[i].LastSeen = clients[i].LastSeen.UTC()
+	}
+
+	return clients
+}